@@ -1,6 +1,6 @@
 module github.com/giantswarm/releaseclient
 
-go 1.14
+go 1.16
 
 require (
 	github.com/Masterminds/semver/v3 v3.1.0
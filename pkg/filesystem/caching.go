@@ -0,0 +1,80 @@
+package filesystem
+
+import (
+	"sync"
+
+	"github.com/giantswarm/apiextensions/v2/pkg/apis/release/v1alpha1"
+	"github.com/giantswarm/microerror"
+)
+
+// Caching is a Filesystem decorator memoizing the content returned by ReadFile, so that files
+// read by several validators like the README or requests.yaml are only read once. Cached
+// content is never invalidated, so a Caching should only live as long as a single read-only
+// validation run. Errors aren't cached. All other calls are passed through.
+type Caching struct {
+	underlying Filesystem
+
+	mutex sync.Mutex
+	files map[string][]byte
+}
+
+func NewCaching(underlying Filesystem) *Caching {
+	return &Caching{
+		underlying: underlying,
+		files:      map[string][]byte{},
+	}
+}
+
+func (f *Caching) Exists(path string) (bool, error) {
+	exists, err := f.underlying.Exists(path)
+	if err != nil {
+		return false, microerror.Mask(err)
+	}
+	return exists, nil
+}
+
+func (f *Caching) FindRelease(provider string, name string, archived bool) (v1alpha1.Release, error) {
+	release, err := f.underlying.FindRelease(provider, name, archived)
+	if err != nil {
+		return v1alpha1.Release{}, microerror.Mask(err)
+	}
+	return release, nil
+}
+
+func (f *Caching) FindReleases(provider string, archived bool) ([]v1alpha1.Release, error) {
+	releases, err := f.underlying.FindReleases(provider, archived)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	return releases, nil
+}
+
+func (f *Caching) ListDirectories(path string) ([]string, error) {
+	names, err := f.underlying.ListDirectories(path)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	return names, nil
+}
+
+// ReadFile returns the cached content of the file at the given path, reading it from the
+// underlying Filesystem on first access. Callers must not modify the returned content.
+func (f *Caching) ReadFile(path string) ([]byte, error) {
+	f.mutex.Lock()
+	content, ok := f.files[path]
+	f.mutex.Unlock()
+	if ok {
+		return content, nil
+	}
+
+	content, err := f.underlying.ReadFile(path)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	f.mutex.Lock()
+	f.files[path] = content
+	f.mutex.Unlock()
+
+	return content, nil
+}
@@ -0,0 +1,61 @@
+package filesystem
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// countingFilesystem counts the ReadFile calls per path before delegating to the embedded
+// Filesystem.
+type countingFilesystem struct {
+	Filesystem
+	calls map[string]int
+}
+
+func (f *countingFilesystem) ReadFile(path string) ([]byte, error) {
+	f.calls[path]++
+	return f.Filesystem.ReadFile(path)
+}
+
+func Test_Caching_ReadFile(t *testing.T) {
+	counting := &countingFilesystem{
+		Filesystem: NewMemory(map[string]string{
+			"README.md":         "# Releases\n",
+			"aws/requests.yaml": "releases: []\n",
+		}),
+		calls: map[string]int{},
+	}
+	fs := NewCaching(counting)
+
+	for i := 0; i < 3; i++ {
+		for _, path := range []string{"README.md", "aws/requests.yaml"} {
+			_, err := fs.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		_, err := fs.ReadFile("aws/README.md")
+		if err == nil {
+			t.Fatal("error == nil, want non-nil")
+		}
+	}
+
+	content, err := fs.ReadFile("README.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "# Releases\n" {
+		t.Errorf("unexpected content %q", content)
+	}
+
+	expected := map[string]int{
+		"README.md":         1,
+		"aws/requests.yaml": 1,
+		// Errors aren't cached.
+		"aws/README.md": 3,
+	}
+	if diff := cmp.Diff(counting.calls, expected); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -1,8 +1,14 @@
 package filesystem
 
 import (
+	"compress/gzip"
 	"io/ioutil"
+	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
 
 	"github.com/giantswarm/apiextensions/v2/pkg/apis/release/v1alpha1"
 	"github.com/giantswarm/microerror"
@@ -11,25 +17,84 @@ import (
 	"github.com/giantswarm/releaseclient/pkg/key"
 )
 
-type Filesystem struct {
-	root string
+const (
+	defaultConcurrency = 8
+	gzipExtension      = ".gz"
+)
+
+// Disk is a Filesystem over a releases repository checked out on local disk.
+type Disk struct {
+	root        string
+	concurrency int
 }
 
-func New(root string) Filesystem {
-	return Filesystem{
-		root: root,
+// Option configures optional behaviour of a Disk.
+type Option func(f *Disk)
+
+// WithConcurrency sets how many release manifests FindReleases reads in parallel.
+func WithConcurrency(concurrency int) Option {
+	return func(f *Disk) {
+		f.concurrency = concurrency
 	}
 }
 
-func (f Filesystem) ReadFile(path string) ([]byte, error) {
-	content, err := ioutil.ReadFile(filepath.Join(f.root, path))
+func New(root string, opts ...Option) Disk {
+	f := Disk{
+		root:        root,
+		concurrency: defaultConcurrency,
+	}
+	for _, opt := range opts {
+		opt(&f)
+	}
+	if f.concurrency < 1 {
+		f.concurrency = 1
+	}
+	return f
+}
+
+// Exists returns whether a file or directory exists at the given path. Like ReadFile it
+// considers a gzip-compressed sibling with a .gz extension.
+func (f Disk) Exists(path string) (bool, error) {
+	for _, candidate := range []string{path, path + gzipExtension} {
+		_, err := os.Stat(filepath.Join(f.root, candidate))
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return false, microerror.Mask(err)
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+func (f Disk) ListDirectories(path string) ([]string, error) {
+	entries, err := ioutil.ReadDir(filepath.Join(f.root, path))
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// ReadFile reads the file at the given path. When it doesn't exist but a gzip-compressed
+// sibling with a .gz extension does, the decompressed content of the sibling is returned.
+func (f Disk) ReadFile(path string) ([]byte, error) {
+	content, err := readFile(filepath.Join(f.root, path))
 	if err != nil {
 		return nil, microerror.Mask(err)
 	}
 	return content, nil
 }
 
-func (f Filesystem) FindRelease(provider string, name string, archived bool) (v1alpha1.Release, error) {
+func (f Disk) FindRelease(provider string, name string, archived bool) (v1alpha1.Release, error) {
 	releases, err := f.FindReleases(provider, archived)
 	if err != nil {
 		return v1alpha1.Release{}, microerror.Mask(err)
@@ -44,10 +109,10 @@ func (f Filesystem) FindRelease(provider string, name string, archived bool) (v1
 	return v1alpha1.Release{}, microerror.Mask(releaseNotFoundError)
 }
 
-func (f Filesystem) FindReleases(provider string, archived bool) ([]v1alpha1.Release, error) {
+func (f Disk) FindReleases(provider string, archived bool) ([]v1alpha1.Release, error) {
 	path := filepath.Join(f.root, provider)
 	if archived {
-		path = filepath.Join(path, "archived")
+		path = filepath.Join(path, key.ArchivedDirectory)
 	}
 
 	releaseDirectories, err := ioutil.ReadDir(path)
@@ -55,29 +120,126 @@ func (f Filesystem) FindReleases(provider string, archived bool) ([]v1alpha1.Rel
 		return nil, microerror.Mask(err)
 	}
 
-	releases := make([]v1alpha1.Release, 0, len(releaseDirectories))
+	var names []string
 	for _, releaseDirectory := range releaseDirectories {
-		if !releaseDirectory.IsDir() || releaseDirectory.Name() == "archived" {
+		if !releaseDirectory.IsDir() || releaseDirectory.Name() == key.ArchivedDirectory {
 			continue
 		}
+		names = append(names, releaseDirectory.Name())
+	}
+	sort.Strings(names)
+
+	// Manifests are read in parallel into slots indexed by the sorted directory names so
+	// that both the releases and the reported error are independent of scheduling.
+	releases := make([]v1alpha1.Release, len(names))
+	errs := make([]error, len(names))
+	{
+		var wg sync.WaitGroup
+		semaphore := make(chan struct{}, f.concurrency)
+		for i := range names {
+			wg.Add(1)
+			semaphore <- struct{}{}
+			go func(i int) {
+				defer wg.Done()
+				defer func() { <-semaphore }()
+				releases[i], errs[i] = readRelease(provider, filepath.Join(path, names[i]))
+			}(i)
+		}
+		wg.Wait()
+	}
 
-		releaseFile := filepath.Join(path, releaseDirectory.Name(), key.ReleaseFilename)
-		data, err := ioutil.ReadFile(releaseFile)
+	for _, err := range errs {
 		if err != nil {
 			return nil, microerror.Mask(err)
 		}
+	}
+
+	return releases, nil
+}
+
+func readRelease(provider string, releaseDirectory string) (v1alpha1.Release, error) {
+	data, err := readFile(filepath.Join(releaseDirectory, key.ReleaseFilename))
+	if err != nil {
+		return v1alpha1.Release{}, microerror.Mask(err)
+	}
 
-		var release v1alpha1.Release
-		err = yaml.Unmarshal(data, &release)
+	release, err := parseRelease(provider, filepath.Base(releaseDirectory), data)
+	if err != nil {
+		return v1alpha1.Release{}, microerror.Mask(err)
+	}
+
+	return release, nil
+}
+
+// readFile reads the file at the given path on disk, falling back to decompressing a .gz
+// sibling if the file itself doesn't exist.
+func readFile(path string) ([]byte, error) {
+	content, err := ioutil.ReadFile(path)
+	if !os.IsNotExist(err) {
 		if err != nil {
 			return nil, microerror.Mask(err)
 		}
-		if releaseDirectory.Name() != release.Name {
-			return nil, microerror.Maskf(invalidReleaseError, "%s release %s is in directory %s which doesn't match its name", provider, release.Name, releaseDirectory)
-		}
+		return content, nil
+	}
 
-		releases = append(releases, release)
+	file, gzipErr := os.Open(path + gzipExtension)
+	if os.IsNotExist(gzipErr) {
+		// Report the missing plain file rather than the missing sibling.
+		return nil, microerror.Mask(err)
+	} else if gzipErr != nil {
+		return nil, microerror.Mask(gzipErr)
 	}
+	defer file.Close()
 
-	return releases, nil
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	defer reader.Close()
+
+	content, err = ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return content, nil
+}
+
+// parseRelease decodes a release manifest read from the given release directory name and
+// checks that the two agree.
+func parseRelease(provider string, directory string, data []byte) (v1alpha1.Release, error) {
+	// The YAML decoder silently ignores every document but the first one.
+	if documents := countDocuments(data); documents > 1 {
+		return v1alpha1.Release{}, microerror.Maskf(invalidReleaseError, "%s release manifest in directory %s contains %d YAML documents, expected exactly one release", provider, directory, documents)
+	}
+
+	var release v1alpha1.Release
+	err := yaml.Unmarshal(data, &release)
+	if err != nil {
+		return v1alpha1.Release{}, microerror.Mask(err)
+	}
+	if directory != release.Name {
+		return v1alpha1.Release{}, microerror.Maskf(invalidReleaseError, "%s release %s is in directory %s which doesn't match its name", provider, release.Name, directory)
+	}
+
+	return release, nil
+}
+
+// documentSeparatorPattern matches the lines separating documents in a YAML stream.
+var documentSeparatorPattern = regexp.MustCompile(`(?m)^---[ \t\r]*(#.*)?$`)
+
+// countDocuments returns the number of YAML documents in data which contain anything but
+// blank lines and comments.
+func countDocuments(data []byte) int {
+	var count int
+	for _, document := range documentSeparatorPattern.Split(string(data), -1) {
+		for _, line := range strings.Split(document, "\n") {
+			line = strings.TrimSpace(line)
+			if line != "" && !strings.HasPrefix(line, "#") {
+				count++
+				break
+			}
+		}
+	}
+	return count
 }
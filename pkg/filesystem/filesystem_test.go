@@ -0,0 +1,277 @@
+package filesystem
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/giantswarm/microerror"
+	"github.com/google/go-cmp/cmp"
+)
+
+// newTestRoot writes the given files into a temporary directory and returns its path. Keys
+// are slash-separated paths relative to the root.
+func newTestRoot(t testing.TB, files map[string]string) string {
+	root, err := ioutil.TempDir("", "releaseclient-filesystem")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = os.RemoveAll(root)
+	})
+
+	for path, content := range files {
+		fullPath := filepath.Join(root, filepath.FromSlash(path))
+		err = os.MkdirAll(filepath.Dir(fullPath), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = ioutil.WriteFile(fullPath, []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return root
+}
+
+func Test_Filesystem_Exists(t *testing.T) {
+	root := newTestRoot(t, map[string]string{
+		"aws/v1.0.0/release.yaml": "metadata:\n  name: v1.0.0\n",
+	})
+
+	testCases := []struct {
+		name     string
+		path     string
+		expected bool
+	}{
+		{
+			name:     "case 0: existing file",
+			path:     "aws/v1.0.0/release.yaml",
+			expected: true,
+		},
+		{
+			name:     "case 1: existing directory",
+			path:     "aws/v1.0.0",
+			expected: true,
+		},
+		{
+			name:     "case 2: missing file",
+			path:     "aws/v1.0.0/README.md",
+			expected: false,
+		},
+		{
+			name:     "case 3: missing directory",
+			path:     "azure",
+			expected: false,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			exists, err := New(root).Exists(tc.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if exists != tc.expected {
+				t.Errorf("expected %t, got %t", tc.expected, exists)
+			}
+		})
+	}
+}
+
+func Test_Filesystem_ReadFile_Gzip(t *testing.T) {
+	manifest := "metadata:\n  name: v1.0.0\n"
+
+	var compressed bytes.Buffer
+	w := gzip.NewWriter(&compressed)
+	_, err := w.Write([]byte(manifest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fs := New(newTestRoot(t, map[string]string{
+		"aws/archived/v1.0.0/release.yaml.gz": compressed.String(),
+		"aws/requests.yaml":                   "releases: []\n",
+		"aws/requests.yaml.gz":                "not gzip",
+	}))
+
+	content, err := fs.ReadFile("aws/archived/v1.0.0/release.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != manifest {
+		t.Errorf("unexpected content %q", content)
+	}
+
+	exists, err := fs.Exists("aws/archived/v1.0.0/release.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !exists {
+		t.Error("expected compressed release manifest to exist")
+	}
+
+	release, err := fs.FindRelease("aws", "v1.0.0", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if release.Name != "v1.0.0" {
+		t.Errorf("unexpected release %s", release.Name)
+	}
+
+	// The plain file takes precedence over its compressed sibling.
+	content, err = fs.ReadFile("aws/requests.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "releases: []\n" {
+		t.Errorf("unexpected content %q", content)
+	}
+
+	_, err = fs.ReadFile("aws/README.md")
+	if !os.IsNotExist(microerror.Cause(err)) {
+		t.Errorf("expected not exist error, got %#v", err)
+	}
+}
+
+func Test_Filesystem_ListDirectories(t *testing.T) {
+	root := newTestRoot(t, map[string]string{
+		"aws/v1.1.0/release.yaml": "",
+		"aws/v1.0.0/release.yaml": "",
+		"aws/requests.yaml":       "",
+	})
+
+	directories, err := New(root).ListDirectories("aws")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(directories, []string{"v1.0.0", "v1.1.0"}); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func releaseFiles(count int) map[string]string {
+	files := map[string]string{}
+	for i := 0; i < count; i++ {
+		name := fmt.Sprintf("v1.%d.0", i)
+		files[fmt.Sprintf("aws/%s/release.yaml", name)] = fmt.Sprintf("metadata:\n  name: %s\n", name)
+	}
+	return files
+}
+
+func Test_Filesystem_FindReleases_Order(t *testing.T) {
+	root := newTestRoot(t, releaseFiles(20))
+
+	var expected []string
+	for i, concurrency := range []int{1, 4, 32} {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			releases, err := New(root, WithConcurrency(concurrency)).FindReleases("aws", false)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var names []string
+			for _, release := range releases {
+				names = append(names, release.Name)
+			}
+			if expected == nil {
+				expected = names
+			}
+			if diff := cmp.Diff(names, expected); diff != "" {
+				t.Errorf("concurrency %d: %s", concurrency, diff)
+			}
+		})
+	}
+}
+
+func Test_Filesystem_FindReleases_Error(t *testing.T) {
+	files := releaseFiles(20)
+	files["aws/v1.13.0/release.yaml"] = "metadata:\n  name: v1.13.1\n"
+	files["aws/v1.3.0/release.yaml"] = "metadata:\n  name: v1.3.1\n"
+	root := newTestRoot(t, files)
+
+	for i, concurrency := range []int{1, 4, 32} {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			_, err := New(root, WithConcurrency(concurrency)).FindReleases("aws", false)
+			if !IsInvalidRelease(err) {
+				t.Fatalf("expected invalid release error, got %#v", err)
+			}
+			// Directory names sort lexicographically, so v1.13.0 comes before v1.3.0.
+			expected := "aws release v1.13.1 is in directory v1.13.0 which doesn't match its name"
+			if err.Error() != fmt.Sprintf("invalid release error: %s", expected) {
+				t.Errorf("concurrency %d: unexpected error %s", concurrency, err)
+			}
+		})
+	}
+}
+
+func Test_Filesystem_FindReleases_MultipleDocuments(t *testing.T) {
+	testCases := []struct {
+		name          string
+		manifest      string
+		errorContains string
+	}{
+		{
+			name:     "case 0: single document with leading separator and trailing comment",
+			manifest: "---\nmetadata:\n  name: v1.0.0\n---\n# end\n",
+		},
+		{
+			name:          "case 1: two documents",
+			manifest:      "metadata:\n  name: v1.0.0\n---\nmetadata:\n  name: v1.1.0\n",
+			errorContains: "aws release manifest in directory v1.0.0 contains 2 YAML documents, expected exactly one release",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			root := newTestRoot(t, map[string]string{
+				"aws/v1.0.0/release.yaml": tc.manifest,
+			})
+
+			releases, err := New(root).FindReleases("aws", false)
+			if tc.errorContains == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(releases) != 1 || releases[0].Name != "v1.0.0" {
+					t.Fatalf("expected release v1.0.0, got %#v", releases)
+				}
+				return
+			}
+			if !IsInvalidRelease(err) || !strings.Contains(err.Error(), tc.errorContains) {
+				t.Fatalf("expected invalid release error containing %q, got %#v", tc.errorContains, err)
+			}
+		})
+	}
+}
+
+func Benchmark_Filesystem_FindReleases(b *testing.B) {
+	root := newTestRoot(b, releaseFiles(200))
+
+	for _, concurrency := range []int{1, defaultConcurrency} {
+		b.Run(strconv.Itoa(concurrency), func(b *testing.B) {
+			fs := New(root, WithConcurrency(concurrency))
+			for n := 0; n < b.N; n++ {
+				_, err := fs.FindReleases("aws", false)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
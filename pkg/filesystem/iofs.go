@@ -0,0 +1,115 @@
+package filesystem
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+
+	"github.com/giantswarm/apiextensions/v2/pkg/apis/release/v1alpha1"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/releaseclient/pkg/key"
+)
+
+// IOFS is a Filesystem over an io/fs file system rooted at the root of a releases repository,
+// e.g. an embed.FS with bundled release templates. Use fs.Sub to select a subdirectory.
+type IOFS struct {
+	fsys fs.FS
+}
+
+// NewIOFS returns an IOFS reading from the given file system.
+func NewIOFS(fsys fs.FS) IOFS {
+	return IOFS{
+		fsys: fsys,
+	}
+}
+
+func (f IOFS) Exists(path string) (bool, error) {
+	_, err := fs.Stat(f.fsys, fsPath(path))
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	} else if err != nil {
+		return false, microerror.Mask(err)
+	}
+	return true, nil
+}
+
+func (f IOFS) ListDirectories(path string) ([]string, error) {
+	entries, err := fs.ReadDir(f.fsys, fsPath(path))
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+
+	return names, nil
+}
+
+func (f IOFS) ReadFile(path string) ([]byte, error) {
+	content, err := fs.ReadFile(f.fsys, fsPath(path))
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	return content, nil
+}
+
+func (f IOFS) FindRelease(provider string, name string, archived bool) (v1alpha1.Release, error) {
+	releases, err := f.FindReleases(provider, archived)
+	if err != nil {
+		return v1alpha1.Release{}, microerror.Mask(err)
+	}
+
+	for _, release := range releases {
+		if release.Name == name {
+			return release, nil
+		}
+	}
+
+	return v1alpha1.Release{}, microerror.Mask(releaseNotFoundError)
+}
+
+func (f IOFS) FindReleases(provider string, archived bool) ([]v1alpha1.Release, error) {
+	directory := provider
+	if archived {
+		directory = path.Join(directory, key.ArchivedDirectory)
+	}
+
+	names, err := f.ListDirectories(directory)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	var releases []v1alpha1.Release
+	for _, name := range names {
+		if name == key.ArchivedDirectory {
+			continue
+		}
+
+		data, err := f.ReadFile(path.Join(directory, name, key.ReleaseFilename))
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		release, err := parseRelease(provider, name, data)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		releases = append(releases, release)
+	}
+
+	return releases, nil
+}
+
+// fsPath converts the given repository path into the form io/fs expects, which names the
+// root ".".
+func fsPath(name string) string {
+	name = cleanPath(name)
+	if name == "" {
+		return "."
+	}
+	return name
+}
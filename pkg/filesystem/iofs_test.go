@@ -0,0 +1,71 @@
+package filesystem
+
+import (
+	"strconv"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_IOFS_ListDirectories(t *testing.T) {
+	fs := NewIOFS(fstest.MapFS{
+		"README.md":                        {},
+		"azure/v1.0.0/release.yaml":        {Data: []byte("metadata:\n  name: v1.0.0\n")},
+		"aws/v1.0.0/release.yaml":          {Data: []byte("metadata:\n  name: v1.0.0\n")},
+		"aws/archived/v0.9.0/release.yaml": {Data: []byte("metadata:\n  name: v0.9.0\n")},
+		"aws/kustomization.yaml":           {},
+	})
+
+	testCases := []struct {
+		name     string
+		path     string
+		expected []string
+	}{
+		{
+			name:     "case 0: repository root",
+			path:     "",
+			expected: []string{"aws", "azure"},
+		},
+		{
+			name:     "case 1: provider directory",
+			path:     "aws",
+			expected: []string{"archived", "v1.0.0"},
+		},
+		{
+			name:     "case 2: unclean path",
+			path:     "./aws/archived/",
+			expected: []string{"v0.9.0"},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			names, err := fs.ListDirectories(tc.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(names, tc.expected); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func Test_IOFS_Exists(t *testing.T) {
+	fs := NewIOFS(fstest.MapFS{
+		"aws/requests.yaml": {},
+	})
+
+	for path, expected := range map[string]bool{"aws": true, "/aws/requests.yaml": true, "azure": false} {
+		exists, err := fs.Exists(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exists != expected {
+			t.Errorf("expected Exists(%q) == %t, got %t", path, expected, exists)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package filesystem
+
+import (
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/giantswarm/apiextensions/v2/pkg/apis/release/v1alpha1"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/releaseclient/pkg/key"
+)
+
+// Memory is a Filesystem over a releases repository held in memory, e.g. for tests or for
+// validating generated content before writing it to disk. Directories exist implicitly as
+// soon as they contain a file.
+type Memory struct {
+	files map[string][]byte
+}
+
+// NewMemory returns a Memory holding the given files. Keys are slash-separated paths
+// relative to the root of the repository.
+func NewMemory(files map[string]string) Memory {
+	f := Memory{
+		files: map[string][]byte{},
+	}
+	for name, content := range files {
+		f.files[cleanPath(name)] = []byte(content)
+	}
+	return f
+}
+
+func (f Memory) Exists(path string) (bool, error) {
+	path = cleanPath(path)
+	if _, ok := f.files[path]; ok {
+		return true, nil
+	}
+
+	prefix := path + "/"
+	if path == "" {
+		prefix = ""
+	}
+	for name := range f.files {
+		if strings.HasPrefix(name, prefix) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (f Memory) ListDirectories(path string) ([]string, error) {
+	path = cleanPath(path)
+	exists, err := f.Exists(path)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	if !exists {
+		return nil, microerror.Mask(&os.PathError{Op: "open", Path: path, Err: os.ErrNotExist})
+	}
+
+	prefix := path + "/"
+	if path == "" {
+		prefix = ""
+	}
+	seen := map[string]bool{}
+	var names []string
+	for name := range f.files {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		segments := strings.SplitN(strings.TrimPrefix(name, prefix), "/", 2)
+		if len(segments) < 2 || seen[segments[0]] {
+			continue
+		}
+		seen[segments[0]] = true
+		names = append(names, segments[0])
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+func (f Memory) ReadFile(path string) ([]byte, error) {
+	content, ok := f.files[cleanPath(path)]
+	if !ok {
+		return nil, microerror.Mask(&os.PathError{Op: "open", Path: path, Err: os.ErrNotExist})
+	}
+	return content, nil
+}
+
+func (f Memory) FindRelease(provider string, name string, archived bool) (v1alpha1.Release, error) {
+	releases, err := f.FindReleases(provider, archived)
+	if err != nil {
+		return v1alpha1.Release{}, microerror.Mask(err)
+	}
+
+	for _, release := range releases {
+		if release.Name == name {
+			return release, nil
+		}
+	}
+
+	return v1alpha1.Release{}, microerror.Mask(releaseNotFoundError)
+}
+
+func (f Memory) FindReleases(provider string, archived bool) ([]v1alpha1.Release, error) {
+	directory := provider
+	if archived {
+		directory = path.Join(directory, key.ArchivedDirectory)
+	}
+
+	names, err := f.ListDirectories(directory)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	var releases []v1alpha1.Release
+	for _, name := range names {
+		if name == key.ArchivedDirectory {
+			continue
+		}
+
+		data, err := f.ReadFile(path.Join(directory, name, key.ReleaseFilename))
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		release, err := parseRelease(provider, name, data)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		releases = append(releases, release)
+	}
+
+	return releases, nil
+}
+
+// cleanPath normalizes the given slash-separated path so that it can be used as a key of
+// Memory.files. The root of the repository is the empty string.
+func cleanPath(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+name), "/")
+}
@@ -0,0 +1,91 @@
+package filesystem
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Memory_ListDirectories(t *testing.T) {
+	fs := NewMemory(map[string]string{
+		"README.md":                        "",
+		"azure/v1.0.0/release.yaml":        "metadata:\n  name: v1.0.0\n",
+		"aws/v1.0.0/release.yaml":          "metadata:\n  name: v1.0.0\n",
+		"aws/archived/v0.9.0/release.yaml": "metadata:\n  name: v0.9.0\n",
+		"aws/kustomization.yaml":           "",
+	})
+
+	testCases := []struct {
+		name     string
+		path     string
+		expected []string
+	}{
+		{
+			name:     "case 0: repository root",
+			path:     "",
+			expected: []string{"aws", "azure"},
+		},
+		{
+			name:     "case 1: provider directory",
+			path:     "aws",
+			expected: []string{"archived", "v1.0.0"},
+		},
+		{
+			name:     "case 2: unclean path",
+			path:     "./aws/archived/",
+			expected: []string{"v0.9.0"},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			names, err := fs.ListDirectories(tc.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(names, tc.expected); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func Test_Memory_FindReleases(t *testing.T) {
+	fs := NewMemory(map[string]string{
+		"aws/v1.1.0/release.yaml":          "metadata:\n  name: v1.1.0\n",
+		"aws/v1.0.0/release.yaml":          "metadata:\n  name: v1.0.0\n",
+		"aws/archived/v0.9.0/release.yaml": "metadata:\n  name: v0.9.0\n",
+	})
+
+	releases, err := fs.FindReleases("aws", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, release := range releases {
+		names = append(names, release.Name)
+	}
+	if diff := cmp.Diff(names, []string{"v1.0.0", "v1.1.0"}); diff != "" {
+		t.Fatal(diff)
+	}
+
+	_, err = fs.FindRelease("aws", "v0.9.0", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = fs.FindRelease("aws", "v0.9.0", false)
+	if !IsReleaseNotFound(err) {
+		t.Fatalf("error == %#v, want release not found", err)
+	}
+
+	exists, err := fs.Exists("aws/archived")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !exists {
+		t.Fatal("expected aws/archived to exist")
+	}
+}
@@ -0,0 +1,122 @@
+package filesystem
+
+import (
+	"path"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/giantswarm/apiextensions/v2/pkg/apis/release/v1alpha1"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/releaseclient/pkg/key"
+)
+
+// GetRelease reads and parses the manifest of a single release without loading the other
+// releases of the provider. Active releases take precedence over archived ones. It returns an
+// error matched by IsReleaseNotFound if neither directory has a manifest.
+func GetRelease(fs Filesystem, provider string, name string) (v1alpha1.Release, error) {
+	candidates := []string{
+		path.Join(provider, name, key.ReleaseFilename),
+		path.Join(provider, key.ArchivedDirectory, name, key.ReleaseFilename),
+	}
+
+	for _, candidate := range candidates {
+		exists, err := fs.Exists(candidate)
+		if err != nil {
+			return v1alpha1.Release{}, microerror.Mask(err)
+		}
+		if !exists {
+			continue
+		}
+
+		data, err := fs.ReadFile(candidate)
+		if err != nil {
+			return v1alpha1.Release{}, microerror.Mask(err)
+		}
+		release, err := parseRelease(provider, name, data)
+		if err != nil {
+			return v1alpha1.Release{}, microerror.Mask(err)
+		}
+		return release, nil
+	}
+
+	return v1alpha1.Release{}, microerror.Maskf(releaseNotFoundError, "%s release %s does not exist", provider, name)
+}
+
+// ListProviders returns the sorted names of the provider directories at the root of the
+// repository. Hidden directories like .github are skipped.
+func ListProviders(fs Filesystem) ([]string, error) {
+	directories, err := fs.ListDirectories("")
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	var providers []string
+	for _, directory := range directories {
+		if strings.HasPrefix(directory, ".") {
+			continue
+		}
+		providers = append(providers, directory)
+	}
+
+	return providers, nil
+}
+
+// Inventory returns the number of active releases of every provider in the repository.
+// Archived releases and releases in another state aren't counted.
+func Inventory(fs Filesystem) (map[string]int, error) {
+	providers, err := ListProviders(fs)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	inventory := map[string]int{}
+	for _, provider := range providers {
+		releases, err := fs.FindReleases(provider, false)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		inventory[provider] = 0
+		for _, release := range releases {
+			if release.Spec.State == v1alpha1.StateActive {
+				inventory[provider]++
+			}
+		}
+	}
+
+	return inventory, nil
+}
+
+// HighestActiveRelease returns the active release of the given provider with the highest
+// semver version. Archived releases aren't considered. It returns an error matched by
+// IsReleaseNotFound if the provider has no active release.
+func HighestActiveRelease(fs Filesystem, provider string) (v1alpha1.Release, error) {
+	releases, err := fs.FindReleases(provider, false)
+	if err != nil {
+		return v1alpha1.Release{}, microerror.Mask(err)
+	}
+
+	var highest *v1alpha1.Release
+	var highestVersion *semver.Version
+	for i, release := range releases {
+		if release.Spec.State != v1alpha1.StateActive {
+			continue
+		}
+
+		version, err := semver.NewVersion(release.Name)
+		if err != nil {
+			return v1alpha1.Release{}, microerror.Maskf(invalidReleaseError, "name of %s release %s must be a valid semver version: %s", provider, release.Name, err)
+		}
+		if highestVersion == nil || version.GreaterThan(highestVersion) {
+			highest = &releases[i]
+			highestVersion = version
+		}
+	}
+
+	if highest == nil {
+		return v1alpha1.Release{}, microerror.Maskf(releaseNotFoundError, "%s has no active release", provider)
+	}
+
+	return *highest, nil
+}
@@ -0,0 +1,137 @@
+package filesystem
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_GetRelease(t *testing.T) {
+	files := map[string]string{
+		"aws/v1.0.0/release.yaml":          "metadata:\n  name: v1.0.0\n",
+		"aws/v1.1.0/release.yaml":          "metadata:\n  name: v1.2.0\n",
+		"aws/archived/v0.9.0/release.yaml": "metadata:\n  name: v0.9.0\n",
+	}
+
+	testCases := []struct {
+		name         string
+		release      string
+		errorMatcher func(error) bool
+	}{
+		{
+			name:    "case 0: active release",
+			release: "v1.0.0",
+		},
+		{
+			name:    "case 1: archived release",
+			release: "v0.9.0",
+		},
+		{
+			name:         "case 2: missing release",
+			release:      "v2.0.0",
+			errorMatcher: IsReleaseNotFound,
+		},
+		{
+			name:         "case 3: name mismatch",
+			release:      "v1.1.0",
+			errorMatcher: IsInvalidRelease,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			for _, fs := range []Filesystem{New(newTestRoot(t, files)), NewMemory(files)} {
+				release, err := GetRelease(fs, "aws", tc.release)
+				switch {
+				case err == nil && tc.errorMatcher == nil:
+					if release.Name != tc.release {
+						t.Fatalf("release.Name == %s, want %s", release.Name, tc.release)
+					}
+				case err != nil && tc.errorMatcher == nil:
+					t.Fatalf("error == %#v, want nil", err)
+				case err == nil && tc.errorMatcher != nil:
+					t.Fatalf("error == nil, want non-nil")
+				case !tc.errorMatcher(err):
+					t.Fatalf("error == %#v, want matching", err)
+				}
+			}
+		})
+	}
+}
+
+func Test_Inventory(t *testing.T) {
+	fs := NewMemory(map[string]string{
+		".github/workflows/ci.yaml":          "",
+		"aws/v1.0.0/release.yaml":            "metadata:\n  name: v1.0.0\nspec:\n  state: deprecated\n",
+		"aws/v1.1.0/release.yaml":            "metadata:\n  name: v1.1.0\nspec:\n  state: active\n",
+		"aws/v1.2.0/release.yaml":            "metadata:\n  name: v1.2.0\nspec:\n  state: active\n",
+		"aws/archived/v0.9.0/release.yaml":   "metadata:\n  name: v0.9.0\nspec:\n  state: active\n",
+		"azure/v2.0.0/release.yaml":          "metadata:\n  name: v2.0.0\nspec:\n  state: active\n",
+		"azure/v2.1.0-beta/release.yaml":     "metadata:\n  name: v2.1.0-beta\nspec:\n  state: wip\n",
+		"azure/archived/v1.9.0/release.yaml": "metadata:\n  name: v1.9.0\nspec:\n  state: deprecated\n",
+	})
+
+	inventory, err := Inventory(fs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]int{
+		"aws":   2,
+		"azure": 1,
+	}
+	if diff := cmp.Diff(inventory, expected); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func Test_HighestActiveRelease(t *testing.T) {
+	testCases := []struct {
+		name         string
+		files        map[string]string
+		expected     string
+		errorMatcher func(error) bool
+	}{
+		{
+			name: "case 0: mix of active and deprecated releases",
+			files: map[string]string{
+				"aws/v9.0.0/release.yaml":           "metadata:\n  name: v9.0.0\nspec:\n  state: active\n",
+				"aws/v10.0.0/release.yaml":          "metadata:\n  name: v10.0.0\nspec:\n  state: active\n",
+				"aws/v10.1.0/release.yaml":          "metadata:\n  name: v10.1.0\nspec:\n  state: deprecated\n",
+				"aws/v11.0.0-beta/release.yaml":     "metadata:\n  name: v11.0.0-beta\nspec:\n  state: wip\n",
+				"aws/archived/v12.0.0/release.yaml": "metadata:\n  name: v12.0.0\nspec:\n  state: active\n",
+			},
+			expected: "v10.0.0",
+		},
+		{
+			name: "case 1: no active release",
+			files: map[string]string{
+				"aws/v10.1.0/release.yaml": "metadata:\n  name: v10.1.0\nspec:\n  state: deprecated\n",
+			},
+			errorMatcher: IsReleaseNotFound,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			release, err := HighestActiveRelease(NewMemory(tc.files), "aws")
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				if release.Name != tc.expected {
+					t.Fatalf("release.Name == %s, want %s", release.Name, tc.expected)
+				}
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case !tc.errorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package filesystem
+
+import (
+	"os"
+	"time"
+
+	"github.com/giantswarm/apiextensions/v2/pkg/apis/release/v1alpha1"
+	"github.com/giantswarm/microerror"
+)
+
+const (
+	defaultRetryAttempts = 3
+	defaultRetryInterval = 100 * time.Millisecond
+)
+
+// Retrying is a Filesystem decorator retrying failed calls of the underlying Filesystem with
+// exponential backoff. It is meant to wrap remote implementations where transient network
+// failures shouldn't abort a whole validation run. Errors which retrying can't fix, like
+// missing files or invalid releases, are returned right away.
+type Retrying struct {
+	underlying Filesystem
+	attempts   int
+	interval   time.Duration
+	// sleep waits between attempts, replaced in tests.
+	sleep func(d time.Duration)
+}
+
+// RetryOption configures optional behaviour of a Retrying.
+type RetryOption func(f *Retrying)
+
+// WithRetryAttempts sets how often a call is attempted in total before its last error is
+// returned. It defaults to 3.
+func WithRetryAttempts(attempts int) RetryOption {
+	return func(f *Retrying) {
+		f.attempts = attempts
+	}
+}
+
+// WithRetryInterval sets the time to wait before the first retry. The interval doubles with
+// every further retry. It defaults to 100ms.
+func WithRetryInterval(interval time.Duration) RetryOption {
+	return func(f *Retrying) {
+		f.interval = interval
+	}
+}
+
+func NewRetrying(underlying Filesystem, opts ...RetryOption) Retrying {
+	f := Retrying{
+		underlying: underlying,
+		attempts:   defaultRetryAttempts,
+		interval:   defaultRetryInterval,
+		sleep:      time.Sleep,
+	}
+	for _, opt := range opts {
+		opt(&f)
+	}
+	if f.attempts < 1 {
+		f.attempts = 1
+	}
+	return f
+}
+
+func (f Retrying) Exists(path string) (bool, error) {
+	var exists bool
+	err := f.retry(func() error {
+		var err error
+		exists, err = f.underlying.Exists(path)
+		return err
+	})
+	if err != nil {
+		return false, microerror.Mask(err)
+	}
+	return exists, nil
+}
+
+func (f Retrying) FindRelease(provider string, name string, archived bool) (v1alpha1.Release, error) {
+	var release v1alpha1.Release
+	err := f.retry(func() error {
+		var err error
+		release, err = f.underlying.FindRelease(provider, name, archived)
+		return err
+	})
+	if err != nil {
+		return v1alpha1.Release{}, microerror.Mask(err)
+	}
+	return release, nil
+}
+
+func (f Retrying) FindReleases(provider string, archived bool) ([]v1alpha1.Release, error) {
+	var releases []v1alpha1.Release
+	err := f.retry(func() error {
+		var err error
+		releases, err = f.underlying.FindReleases(provider, archived)
+		return err
+	})
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	return releases, nil
+}
+
+func (f Retrying) ListDirectories(path string) ([]string, error) {
+	var names []string
+	err := f.retry(func() error {
+		var err error
+		names, err = f.underlying.ListDirectories(path)
+		return err
+	})
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	return names, nil
+}
+
+func (f Retrying) ReadFile(path string) ([]byte, error) {
+	var content []byte
+	err := f.retry(func() error {
+		var err error
+		content, err = f.underlying.ReadFile(path)
+		return err
+	})
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	return content, nil
+}
+
+// retry calls o until it succeeds, fails permanently or runs out of attempts and returns its
+// last error.
+func (f Retrying) retry(o func() error) error {
+	interval := f.interval
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = o()
+		if err == nil || isPermanent(err) || attempt >= f.attempts {
+			break
+		}
+		f.sleep(interval)
+		interval *= 2
+	}
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	return nil
+}
+
+func isPermanent(err error) bool {
+	return IsInvalidRelease(err) || IsReleaseNotFound(err) || os.IsNotExist(microerror.Cause(err))
+}
@@ -0,0 +1,96 @@
+package filesystem
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// flakyFilesystem fails the first failures calls of ReadFile with a transient error before
+// delegating to the embedded Filesystem.
+type flakyFilesystem struct {
+	Filesystem
+	failures int
+	calls    int
+}
+
+func (f *flakyFilesystem) ReadFile(path string) ([]byte, error) {
+	f.calls++
+	if f.calls <= f.failures {
+		return nil, errors.New("connection reset by peer")
+	}
+	return f.Filesystem.ReadFile(path)
+}
+
+func Test_Retrying_ReadFile(t *testing.T) {
+	testCases := []struct {
+		name          string
+		failures      int
+		path          string
+		expectedCalls int
+		expectedSleep []time.Duration
+		expectError   bool
+	}{
+		{
+			name:          "case 0: succeeds after two transient failures",
+			failures:      2,
+			path:          "aws/requests.yaml",
+			expectedCalls: 3,
+			expectedSleep: []time.Duration{time.Second, 2 * time.Second},
+		},
+		{
+			name:          "case 1: gives up after exhausting attempts",
+			failures:      3,
+			path:          "aws/requests.yaml",
+			expectedCalls: 3,
+			expectedSleep: []time.Duration{time.Second, 2 * time.Second},
+			expectError:   true,
+		},
+		{
+			name:          "case 2: missing file isn't retried",
+			path:          "aws/README.md",
+			expectedCalls: 1,
+			expectError:   true,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			flaky := &flakyFilesystem{
+				Filesystem: NewMemory(map[string]string{
+					"aws/requests.yaml": "releases: []\n",
+				}),
+				failures: tc.failures,
+			}
+			var slept []time.Duration
+			fs := NewRetrying(flaky, WithRetryInterval(time.Second))
+			fs.sleep = func(d time.Duration) {
+				slept = append(slept, d)
+			}
+
+			content, err := fs.ReadFile(tc.path)
+			if tc.expectError && err == nil {
+				t.Fatal("error == nil, want non-nil")
+			}
+			if !tc.expectError {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(content) != "releases: []\n" {
+					t.Errorf("unexpected content %q", content)
+				}
+			}
+			if flaky.calls != tc.expectedCalls {
+				t.Errorf("calls == %d, want %d", flaky.calls, tc.expectedCalls)
+			}
+			if diff := cmp.Diff(slept, tc.expectedSleep); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,15 @@
+package filesystem
+
+import "github.com/giantswarm/apiextensions/v2/pkg/apis/release/v1alpha1"
+
+// Filesystem provides access to the files and releases of a releases repository. All paths
+// are relative to the root of the repository.
+type Filesystem interface {
+	// Exists returns whether a file or directory exists at the given path without reading it.
+	Exists(path string) (bool, error)
+	FindRelease(provider string, name string, archived bool) (v1alpha1.Release, error)
+	FindReleases(provider string, archived bool) ([]v1alpha1.Release, error)
+	// ListDirectories returns the sorted names of the directories directly inside the given path.
+	ListDirectories(path string) ([]string, error)
+	ReadFile(path string) ([]byte, error)
+}
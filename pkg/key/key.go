@@ -1,8 +1,67 @@
 package key
 
+import (
+	"strings"
+	"time"
+
+	"github.com/giantswarm/apiextensions/v2/pkg/apis/release/v1alpha1"
+	"github.com/giantswarm/microerror"
+)
+
 const (
+	ArchivedDirectory     = "archived"
 	KustomizationFilename = "kustomization.yaml"
 	ReadmeFilename        = "README.md"
 	ReleaseFilename       = "release.yaml"
 	RequestsFilename      = "requests.yaml"
+
+	KustomizationAPIVersion = "kustomize.config.k8s.io/v1beta1"
+	KustomizationKind       = "Kustomization"
+
+	// ProviderLabel names the provider a release belongs to.
+	ProviderLabel = "giantswarm.io/provider"
+
+	RepositoryURL = "https://github.com/giantswarm/releases"
+
+	// SunsetAnnotation holds the date, formatted as SunsetDateFormat, after which a
+	// deprecated release is no longer supported. spec.endOfLifeDate takes precedence.
+	SunsetAnnotation = "release.giantswarm.io/sunset"
+	SunsetDateFormat = "2006-01-02"
 )
+
+// NormalizeVersion returns the canonical form of a release name or version, which
+// is the version with a single leading `v`. Both "1.2.0" and "v1.2.0" become
+// "v1.2.0". Empty names are returned unchanged.
+func NormalizeVersion(name string) string {
+	name = strings.TrimPrefix(strings.TrimSpace(name), "v")
+	if name == "" {
+		return ""
+	}
+	return "v" + name
+}
+
+// ReleaseStates lists every state the Release CRD accepts.
+var ReleaseStates = []v1alpha1.ReleaseState{
+	v1alpha1.StateActive,
+	v1alpha1.StateDeprecated,
+	v1alpha1.StateWIP,
+}
+
+// SunsetDate returns the end of life date of the release taken from spec.endOfLifeDate or,
+// for manifests predating that field, the SunsetAnnotation. It returns nil if neither is set.
+func SunsetDate(release v1alpha1.Release) (*time.Time, error) {
+	if release.Spec.EndOfLifeDate != nil {
+		date := release.Spec.EndOfLifeDate.Time
+		return &date, nil
+	}
+
+	value, ok := release.Annotations[SunsetAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	date, err := time.Parse(SunsetDateFormat, value)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	return &date, nil
+}
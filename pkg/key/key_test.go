@@ -0,0 +1,138 @@
+package key
+
+import (
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/giantswarm/apiextensions/v2/pkg/apis/release/v1alpha1"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_NormalizeVersion(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "case 0: name with prefix is unchanged",
+			input:    "v11.2.0",
+			expected: "v11.2.0",
+		},
+		{
+			name:     "case 1: name without prefix gains one",
+			input:    "11.2.0",
+			expected: "v11.2.0",
+		},
+		{
+			name:     "case 2: pre-release without prefix",
+			input:    "12.0.0-beta.1",
+			expected: "v12.0.0-beta.1",
+		},
+		{
+			name:     "case 3: surrounding whitespace is trimmed",
+			input:    " v11.2.0\n",
+			expected: "v11.2.0",
+		},
+		{
+			name:     "case 4: empty name",
+			input:    "",
+			expected: "",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			result := NormalizeVersion(tc.input)
+			if result != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, result)
+			}
+		})
+	}
+}
+
+func Test_ReleaseStates(t *testing.T) {
+	crd := v1alpha1.NewReleaseCRD()
+	for _, version := range crd.Spec.Versions {
+		pattern := version.Schema.OpenAPIV3Schema.Properties["spec"].Properties["state"].Pattern
+		if pattern == "" {
+			t.Fatalf("CRD version %s doesn't restrict the release state", version.Name)
+		}
+		accepted := regexp.MustCompile(pattern)
+
+		for _, state := range ReleaseStates {
+			if !accepted.MatchString(state.String()) {
+				t.Errorf("CRD version %s doesn't accept state %s", version.Name, state)
+			}
+		}
+
+		// The CRD must not accept anything beyond ReleaseStates.
+		var expected string
+		for i, state := range ReleaseStates {
+			if i > 0 {
+				expected += "|"
+			}
+			expected += state.String()
+		}
+		if pattern != "^("+expected+")$" {
+			t.Errorf("CRD version %s accepts states %s, expected ^(%s)$", version.Name, pattern, expected)
+		}
+	}
+}
+
+func Test_SunsetDate(t *testing.T) {
+	testCases := []struct {
+		name        string
+		annotations map[string]string
+		endOfLife   *metav1.Time
+		expected    *time.Time
+		errorWanted bool
+	}{
+		{
+			name: "case 0: no annotation",
+		},
+		{
+			name:        "case 1: valid date",
+			annotations: map[string]string{SunsetAnnotation: "2021-03-31"},
+			expected:    timePtr(time.Date(2021, 3, 31, 0, 0, 0, 0, time.UTC)),
+		},
+		{
+			name:        "case 2: invalid date",
+			annotations: map[string]string{SunsetAnnotation: "31.03.2021"},
+			errorWanted: true,
+		},
+		{
+			name:        "case 3: end of life date takes precedence",
+			annotations: map[string]string{SunsetAnnotation: "2021-03-31"},
+			endOfLife:   &metav1.Time{Time: time.Date(2021, 6, 30, 12, 0, 0, 0, time.UTC)},
+			expected:    timePtr(time.Date(2021, 6, 30, 12, 0, 0, 0, time.UTC)),
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			release := v1alpha1.Release{}
+			release.Annotations = tc.annotations
+			release.Spec.EndOfLifeDate = tc.endOfLife
+
+			result, err := SunsetDate(release)
+			if (err != nil) != tc.errorWanted {
+				t.Fatalf("expected error %t, got %v", tc.errorWanted, err)
+			}
+			if diff := cmp.Diff(result, tc.expected); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
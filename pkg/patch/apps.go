@@ -40,7 +40,7 @@ func patchApps(base []v1alpha1.ReleaseSpecApp, patch []AppPatch) []v1alpha1.Rele
 			}
 
 			if !exists {
-				newAppFromPatch := patchApp(v1alpha1.ReleaseSpecApp{}, app)
+				newAppFromPatch := patchApp(v1alpha1.ReleaseSpecApp{Name: app.Name}, app)
 				result = append(result, newAppFromPatch)
 			}
 		} else if app.Change == ChangeDelete {
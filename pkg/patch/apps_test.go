@@ -65,3 +65,20 @@ func Test_App_Patch(t *testing.T) {
 		})
 	}
 }
+
+func Test_Apps_Patch_Add(t *testing.T) {
+	base := []v1alpha1.ReleaseSpecApp{
+		{Name: "cert-exporter", Version: "1.2.3"},
+	}
+	patch := []AppPatch{
+		{Change: ChangeAdd, Name: "coredns", ComponentVersion: stringPtr("1.6.5"), Version: stringPtr("1.1.0")},
+	}
+
+	expected := []v1alpha1.ReleaseSpecApp{
+		{Name: "cert-exporter", Version: "1.2.3"},
+		{Name: "coredns", ComponentVersion: "1.6.5", Version: "1.1.0"},
+	}
+	if diff := cmp.Diff(patchApps(base, patch), expected); diff != "" {
+		t.Error(diff)
+	}
+}
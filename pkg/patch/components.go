@@ -1,7 +1,9 @@
 package patch
 
 import (
+	"github.com/Masterminds/semver/v3"
 	"github.com/giantswarm/apiextensions/v2/pkg/apis/release/v1alpha1"
+	"github.com/giantswarm/microerror"
 )
 
 type ComponentPatch struct {
@@ -48,7 +50,7 @@ func patchComponents(base []v1alpha1.ReleaseSpecComponent, patch []ComponentPatc
 			}
 
 			if !exists {
-				newComponentFromPatch := patchComponent(v1alpha1.ReleaseSpecComponent{}, component)
+				newComponentFromPatch := patchComponent(v1alpha1.ReleaseSpecComponent{Name: component.Name}, component)
 				result = append(result, newComponentFromPatch)
 			}
 		} else if component.Change == ChangeDelete {
@@ -63,3 +65,42 @@ func patchComponents(base []v1alpha1.ReleaseSpecComponent, patch []ComponentPatc
 
 	return result
 }
+
+// BumpComponent sets the version of the named component to newVersion in every active release
+// shipping that component and returns copies of the modified releases, in the given order, for
+// the caller to persist. Releases already at newVersion and the given releases themselves are
+// left untouched.
+func BumpComponent(releases []v1alpha1.Release, name string, newVersion string) ([]v1alpha1.Release, error) {
+	_, err := semver.StrictNewVersion(newVersion)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	patch := []ComponentPatch{
+		{
+			Change:  ChangeModify,
+			Name:    name,
+			Version: stringPtr(newVersion),
+		},
+	}
+
+	var bumped []v1alpha1.Release
+	for _, release := range releases {
+		if release.Spec.State != v1alpha1.StateActive {
+			continue
+		}
+
+		for _, component := range release.Spec.Components {
+			if component.Name != name || component.Version == newVersion {
+				continue
+			}
+
+			modified := *release.DeepCopy()
+			modified.Spec.Components = patchComponents(release.Spec.Components, patch)
+			bumped = append(bumped, modified)
+			break
+		}
+	}
+
+	return bumped, nil
+}
@@ -73,3 +73,61 @@ func Test_Component_Patch(t *testing.T) {
 		})
 	}
 }
+
+func Test_Components_Patch_Add(t *testing.T) {
+	base := []v1alpha1.ReleaseSpecComponent{
+		{Name: "kubernetes", Version: "1.18.9"},
+	}
+	patch := []ComponentPatch{
+		{Change: ChangeAdd, Name: "containerlinux", Version: stringPtr("2605.6.0")},
+	}
+
+	expected := []v1alpha1.ReleaseSpecComponent{
+		{Name: "kubernetes", Version: "1.18.9"},
+		{Name: "containerlinux", Version: "2605.6.0"},
+	}
+	if diff := cmp.Diff(patchComponents(base, patch), expected); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func Test_BumpComponent(t *testing.T) {
+	release := func(name string, state v1alpha1.ReleaseState, kubernetes string) v1alpha1.Release {
+		r := v1alpha1.Release{}
+		r.Name = name
+		r.Spec.State = state
+		r.Spec.Components = []v1alpha1.ReleaseSpecComponent{
+			{Name: "calico", Version: "3.15.1"},
+			{Name: "kubernetes", Version: kubernetes},
+		}
+		return r
+	}
+
+	releases := []v1alpha1.Release{
+		release("v1.0.0", v1alpha1.StateDeprecated, "1.18.9"),
+		release("v1.1.0", v1alpha1.StateActive, "1.18.9"),
+		release("v1.2.0", v1alpha1.StateActive, "1.19.4"),
+		release("v1.3.0", v1alpha1.StateActive, "1.19.5"),
+	}
+
+	bumped, err := BumpComponent(releases, "kubernetes", "1.19.5")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []v1alpha1.Release{
+		release("v1.1.0", v1alpha1.StateActive, "1.19.5"),
+		release("v1.2.0", v1alpha1.StateActive, "1.19.5"),
+	}
+	if diff := cmp.Diff(bumped, expected); diff != "" {
+		t.Error(diff)
+	}
+	if releases[1].Spec.Components[1].Version != "1.18.9" {
+		t.Errorf("expected the given releases to be left untouched, got kubernetes %s", releases[1].Spec.Components[1].Version)
+	}
+
+	_, err = BumpComponent(releases, "kubernetes", "1.19")
+	if err == nil {
+		t.Error("expected an error for an incomplete version")
+	}
+}
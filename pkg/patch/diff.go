@@ -0,0 +1,147 @@
+package patch
+
+import (
+	"github.com/Masterminds/semver/v3"
+	"github.com/giantswarm/apiextensions/v2/pkg/apis/release/v1alpha1"
+	"github.com/giantswarm/microerror"
+)
+
+// Diff returns the patch which turns the previous release into the current one when passed to
+// Apply. Patches only carry the fields which changed, additions carry all fields and are
+// listed in the order of the current release followed by deletions in the order of the
+// previous one.
+func Diff(previous v1alpha1.Release, current v1alpha1.Release) (ReleasePatch, error) {
+	version, err := semver.NewVersion(current.Name)
+	if err != nil {
+		return ReleasePatch{}, microerror.Mask(err)
+	}
+
+	patch := ReleasePatch{
+		Version:    *version,
+		Apps:       diffApps(previous.Spec.Apps, current.Spec.Apps),
+		Components: diffComponents(previous.Spec.Components, current.Spec.Components),
+	}
+	if current.Spec.Date != nil {
+		patch.Date = *current.Spec.Date
+	}
+
+	return patch, nil
+}
+
+func diffApps(previous []v1alpha1.ReleaseSpecApp, current []v1alpha1.ReleaseSpecApp) []AppPatch {
+	previousApps := map[string]v1alpha1.ReleaseSpecApp{}
+	for _, app := range previous {
+		previousApps[app.Name] = app
+	}
+
+	var result []AppPatch
+	currentApps := map[string]bool{}
+	for _, app := range current {
+		currentApps[app.Name] = true
+
+		existing, ok := previousApps[app.Name]
+		if !ok {
+			patch := AppPatch{
+				Change:  ChangeAdd,
+				Name:    app.Name,
+				Version: stringPtr(app.Version),
+			}
+			if app.ComponentVersion != "" {
+				patch.ComponentVersion = stringPtr(app.ComponentVersion)
+			}
+			result = append(result, patch)
+			continue
+		}
+
+		if existing == app {
+			continue
+		}
+		patch := AppPatch{
+			Change: ChangeModify,
+			Name:   app.Name,
+		}
+		if existing.Version != app.Version {
+			patch.Version = stringPtr(app.Version)
+		}
+		if existing.ComponentVersion != app.ComponentVersion {
+			patch.ComponentVersion = stringPtr(app.ComponentVersion)
+		}
+		result = append(result, patch)
+	}
+
+	for _, app := range previous {
+		if !currentApps[app.Name] {
+			result = append(result, AppPatch{
+				Change: ChangeDelete,
+				Name:   app.Name,
+			})
+		}
+	}
+
+	return result
+}
+
+func diffComponents(previous []v1alpha1.ReleaseSpecComponent, current []v1alpha1.ReleaseSpecComponent) []ComponentPatch {
+	previousComponents := map[string]v1alpha1.ReleaseSpecComponent{}
+	for _, component := range previous {
+		previousComponents[component.Name] = component
+	}
+
+	var result []ComponentPatch
+	currentComponents := map[string]bool{}
+	for _, component := range current {
+		currentComponents[component.Name] = true
+
+		existing, ok := previousComponents[component.Name]
+		if !ok {
+			patch := ComponentPatch{
+				Change:  ChangeAdd,
+				Name:    component.Name,
+				Version: stringPtr(component.Version),
+			}
+			if component.Catalog != "" {
+				patch.Catalog = stringPtr(component.Catalog)
+			}
+			if component.Reference != "" {
+				patch.Reference = stringPtr(component.Reference)
+			}
+			if component.ReleaseOperatorDeploy {
+				patch.ReleaseOperatorDeploy = boolPtr(component.ReleaseOperatorDeploy)
+			}
+			result = append(result, patch)
+			continue
+		}
+
+		if existing == component {
+			continue
+		}
+		patch := ComponentPatch{
+			Change: ChangeModify,
+			Name:   component.Name,
+		}
+		if existing.Version != component.Version {
+			patch.Version = stringPtr(component.Version)
+		}
+		if existing.Catalog != component.Catalog {
+			patch.Catalog = stringPtr(component.Catalog)
+		}
+		if existing.Reference != component.Reference {
+			patch.Reference = stringPtr(component.Reference)
+		}
+		if existing.ReleaseOperatorDeploy != component.ReleaseOperatorDeploy {
+			patch.ReleaseOperatorDeploy = boolPtr(component.ReleaseOperatorDeploy)
+		}
+		result = append(result, patch)
+	}
+
+	for _, component := range previous {
+		if !currentComponents[component.Name] {
+			result = append(result, ComponentPatch{
+				Change: ChangeDelete,
+				Name:   component.Name,
+			})
+		}
+	}
+
+	return result
+}
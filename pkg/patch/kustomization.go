@@ -0,0 +1,96 @@
+package patch
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/giantswarm/microerror"
+	"sigs.k8s.io/yaml"
+
+	"github.com/giantswarm/releaseclient/pkg/key"
+)
+
+// releaseKustomization is the kustomization.yaml of a single release directory.
+type releaseKustomization struct {
+	APIVersion string   `json:"apiVersion"`
+	Kind       string   `json:"kind"`
+	Resources  []string `json:"resources"`
+}
+
+// AddReleaseKustomization proposes the kustomization changes for adding the named release to
+// a provider without writing anything. It returns the given provider kustomization.yaml with
+// the release inserted into its resources, which are kept sorted by version, and the
+// kustomization.yaml for the new release directory. Fields of the provider kustomization
+// other than resources are kept but comments and key order are not.
+func AddReleaseKustomization(providerKustomization []byte, release string) (updated []byte, created []byte, err error) {
+	var document map[string]interface{}
+	err = yaml.Unmarshal(providerKustomization, &document)
+	if err != nil {
+		return nil, nil, microerror.Mask(err)
+	}
+	if document == nil {
+		document = map[string]interface{}{}
+	}
+
+	var resources []string
+	if raw, ok := document["resources"]; ok && raw != nil {
+		list, ok := raw.([]interface{})
+		if !ok {
+			return nil, nil, microerror.Mask(fmt.Errorf("resources in %s must be a list", key.KustomizationFilename))
+		}
+		for _, item := range list {
+			resource, ok := item.(string)
+			if !ok {
+				return nil, nil, microerror.Mask(fmt.Errorf("resources in %s must be strings, got %v", key.KustomizationFilename, item))
+			}
+			resources = append(resources, resource)
+		}
+	}
+
+	position := len(resources)
+	for i, resource := range resources {
+		if cleanResource(resource) == release {
+			return nil, nil, microerror.Mask(fmt.Errorf("release %s is already registered in %s", release, key.KustomizationFilename))
+		}
+		if position == len(resources) && resourceLess(release, cleanResource(resource)) {
+			position = i
+		}
+	}
+	resources = append(resources[:position], append([]string{release}, resources[position:]...)...)
+	document["resources"] = resources
+
+	updated, err = yaml.Marshal(document)
+	if err != nil {
+		return nil, nil, microerror.Mask(err)
+	}
+
+	created, err = yaml.Marshal(releaseKustomization{
+		APIVersion: key.KustomizationAPIVersion,
+		Kind:       key.KustomizationKind,
+		Resources:  []string{key.ReleaseFilename},
+	})
+	if err != nil {
+		return nil, nil, microerror.Mask(err)
+	}
+
+	return updated, created, nil
+}
+
+// cleanResource strips relative path decorations like "./v1.2.0" or "v1.2.0/" from a
+// kustomization resource.
+func cleanResource(resource string) string {
+	return path.Clean(filepath.ToSlash(resource))
+}
+
+// resourceLess orders release resources by semver, falling back to lexical order for
+// resources which aren't versions.
+func resourceLess(a string, b string) bool {
+	versionA, errA := semver.NewVersion(a)
+	versionB, errB := semver.NewVersion(b)
+	if errA == nil && errB == nil {
+		return versionA.LessThan(versionB)
+	}
+	return a < b
+}
@@ -0,0 +1,90 @@
+package patch
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_AddReleaseKustomization(t *testing.T) {
+	testCases := []struct {
+		name          string
+		provider      string
+		release       string
+		expected      string
+		errorExpected bool
+	}{
+		{
+			name: "case 0: release inserted in sorted order",
+			provider: `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- v9.3.0
+- v11.0.0
+- v11.2.0
+`,
+			release: "v11.1.0",
+			expected: `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- v9.3.0
+- v11.0.0
+- v11.1.0
+- v11.2.0
+`,
+		},
+		{
+			name: "case 1: newest release appended and other fields kept",
+			provider: `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+commonAnnotations:
+  giantswarm.io/docs: https://docs.giantswarm.io
+resources:
+- ./v11.0.0
+`,
+			release: "v12.0.0",
+			expected: `apiVersion: kustomize.config.k8s.io/v1beta1
+commonAnnotations:
+  giantswarm.io/docs: https://docs.giantswarm.io
+kind: Kustomization
+resources:
+- ./v11.0.0
+- v12.0.0
+`,
+		},
+		{
+			name: "case 2: release already registered",
+			provider: `resources:
+- ./v11.0.0
+`,
+			release:       "v11.0.0",
+			errorExpected: true,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			updated, created, err := AddReleaseKustomization([]byte(tc.provider), tc.release)
+			if tc.errorExpected {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(string(updated), tc.expected); diff != "" {
+				t.Error(diff)
+			}
+			expectedCreated := "apiVersion: kustomize.config.k8s.io/v1beta1\nkind: Kustomization\nresources:\n- release.yaml\n"
+			if diff := cmp.Diff(string(created), expectedCreated); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package patch
+
+import (
+	"encoding/json"
+
+	"github.com/giantswarm/apiextensions/v2/pkg/apis/release/v1alpha1"
+	"github.com/giantswarm/microerror"
+	"sigs.k8s.io/yaml"
+)
+
+// MarshalRelease returns the release as a release.yaml following the repository convention: the
+// Release type meta is always set, keys are sorted, dates are written as UTC timestamps like
+// 2020-09-01T12:00:00Z and the status as well as an unset creation timestamp are omitted.
+func MarshalRelease(release v1alpha1.Release) ([]byte, error) {
+	release.TypeMeta = v1alpha1.NewReleaseTypeMeta()
+
+	// The release is converted to a generic document first to drop the fields which the
+	// v1alpha1 types always marshal.
+	data, err := json.Marshal(release)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	var document map[string]interface{}
+	err = json.Unmarshal(data, &document)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	delete(document, "status")
+	if metadata, ok := document["metadata"].(map[string]interface{}); ok && metadata["creationTimestamp"] == nil {
+		delete(metadata, "creationTimestamp")
+	}
+
+	data, err = yaml.Marshal(document)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,75 @@
+package patch
+
+import (
+	"testing"
+
+	"github.com/giantswarm/apiextensions/v2/pkg/apis/release/v1alpha1"
+	"github.com/google/go-cmp/cmp"
+	"sigs.k8s.io/yaml"
+)
+
+func Test_MarshalRelease(t *testing.T) {
+	input := `kind: Release
+metadata:
+  name: v1.0.0
+  annotations:
+    giantswarm.io/docs: https://docs.giantswarm.io/reference/cp-k8s-api/releases.release.giantswarm.io/
+spec:
+  state: active
+  date: 2020-09-01T14:00:00+02:00
+  apps:
+  - version: 1.2.0
+    name: coredns
+    componentVersion: 1.6.5
+  components:
+  - version: 1.18.9
+    name: kubernetes
+status:
+  ready: true
+`
+	expected := `apiVersion: release.giantswarm.io/v1alpha1
+kind: Release
+metadata:
+  annotations:
+    giantswarm.io/docs: https://docs.giantswarm.io/reference/cp-k8s-api/releases.release.giantswarm.io/
+  name: v1.0.0
+spec:
+  apps:
+  - componentVersion: 1.6.5
+    name: coredns
+    version: 1.2.0
+  components:
+  - name: kubernetes
+    version: 1.18.9
+  date: "2020-09-01T12:00:00Z"
+  state: active
+`
+
+	var release v1alpha1.Release
+	err := yaml.Unmarshal([]byte(input), &release)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := MarshalRelease(release)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(string(data), expected); diff != "" {
+		t.Fatal(diff)
+	}
+
+	// Marshaling the parsed output again must not change a single byte.
+	var roundTripped v1alpha1.Release
+	err = yaml.Unmarshal(data, &roundTripped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := MarshalRelease(roundTripped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(string(again), string(data)); diff != "" {
+		t.Fatal(diff)
+	}
+}
@@ -1,11 +1,11 @@
 package patch
 
 import (
-	"fmt"
-
 	"github.com/Masterminds/semver/v3"
 	"github.com/giantswarm/apiextensions/v2/pkg/apis/release/v1alpha1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/giantswarm/releaseclient/pkg/key"
 )
 
 type ReleasePatch struct {
@@ -19,17 +19,17 @@ type ReleasePatch struct {
 // Apply patch to the given base release and return the resulting previous and patched releases.
 func Apply(base v1alpha1.Release, patch ReleasePatch) (previous v1alpha1.Release, patched v1alpha1.Release) {
 	previous = base
-	previous.Spec.State = "deprecated"
+	previous.Spec.State = v1alpha1.StateDeprecated
 
 	patched = v1alpha1.Release{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: fmt.Sprintf("v%s", patch.Version.String()),
+			Name: key.NormalizeVersion(patch.Version.String()),
 		},
 		Spec: v1alpha1.ReleaseSpec{
 			Apps:       patchApps(base.Spec.Apps, patch.Apps),
 			Components: patchComponents(base.Spec.Components, patch.Components),
 			Date:       timePtr(patch.Date),
-			State:      "active",
+			State:      v1alpha1.StateActive,
 		},
 		Status: v1alpha1.ReleaseStatus{},
 	}
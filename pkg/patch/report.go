@@ -0,0 +1,88 @@
+package patch
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/giantswarm/apiextensions/v2/pkg/apis/release/v1alpha1"
+)
+
+// RenderReleaseReport returns a Markdown summary of the current release listing its date,
+// state, components and apps followed by the changes since the previous release. The changes
+// are omitted when previous is the zero value.
+func RenderReleaseReport(current v1alpha1.Release, previous v1alpha1.Release) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Release %s\n\n", current.Name)
+	if current.Spec.Date != nil {
+		fmt.Fprintf(&b, "- Date: %s\n", current.Spec.Date.UTC().Format("2006-01-02"))
+	}
+	fmt.Fprintf(&b, "- State: %s\n", current.Spec.State)
+
+	if len(current.Spec.Components) > 0 {
+		b.WriteString("\n## Components\n\n| Name | Version |\n| --- | --- |\n")
+		for _, component := range current.Spec.Components {
+			fmt.Fprintf(&b, "| %s | %s |\n", component.Name, component.Version)
+		}
+	}
+
+	if len(current.Spec.Apps) > 0 {
+		b.WriteString("\n## Apps\n\n| Name | Version | Component version |\n| --- | --- | --- |\n")
+		for _, app := range current.Spec.Apps {
+			fmt.Fprintf(&b, "| %s | %s | %s |\n", app.Name, app.Version, app.ComponentVersion)
+		}
+	}
+
+	if previous.Name == "" {
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "\n## Changes since %s\n", previous.Name)
+
+	previousComponents := map[string]string{}
+	for _, component := range previous.Spec.Components {
+		previousComponents[component.Name] = component.Version
+	}
+	componentPatches := diffComponents(previous.Spec.Components, current.Spec.Components)
+
+	previousApps := map[string]string{}
+	for _, app := range previous.Spec.Apps {
+		previousApps[app.Name] = app.Version
+	}
+	appPatches := diffApps(previous.Spec.Apps, current.Spec.Apps)
+
+	if len(componentPatches) == 0 && len(appPatches) == 0 {
+		b.WriteString("\nNo changes.\n")
+		return b.String()
+	}
+
+	if len(componentPatches) > 0 {
+		b.WriteString("\n### Components\n\n")
+		for _, patch := range componentPatches {
+			b.WriteString(describeChange(patch.Change, patch.Name, previousComponents[patch.Name], patch.Version))
+		}
+	}
+
+	if len(appPatches) > 0 {
+		b.WriteString("\n### Apps\n\n")
+		for _, patch := range appPatches {
+			b.WriteString(describeChange(patch.Change, patch.Name, previousApps[patch.Name], patch.Version))
+		}
+	}
+
+	return b.String()
+}
+
+// describeChange returns a Markdown list item describing a single component or app change.
+func describeChange(change Change, name string, previousVersion string, version *string) string {
+	switch {
+	case change == ChangeAdd:
+		return fmt.Sprintf("- Added %s %s\n", name, *version)
+	case change == ChangeDelete:
+		return fmt.Sprintf("- Removed %s %s\n", name, previousVersion)
+	case version != nil:
+		return fmt.Sprintf("- Updated %s from %s to %s\n", name, previousVersion, *version)
+	default:
+		return fmt.Sprintf("- Changed %s %s\n", name, previousVersion)
+	}
+}
@@ -0,0 +1,93 @@
+package patch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/giantswarm/apiextensions/v2/pkg/apis/release/v1alpha1"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_RenderReleaseReport(t *testing.T) {
+	previous := v1alpha1.Release{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "v1.0.0",
+		},
+		Spec: v1alpha1.ReleaseSpec{
+			Apps: []v1alpha1.ReleaseSpecApp{
+				{Name: "cert-exporter", Version: "1.2.3"},
+				{Name: "coredns", ComponentVersion: "1.6.5", Version: "1.1.0"},
+			},
+			Components: []v1alpha1.ReleaseSpecComponent{
+				{Name: "kubernetes", Version: "1.18.9"},
+				{Name: "calico", Version: "3.15.1"},
+				{Name: "etcd", Version: "3.4.9"},
+			},
+			State: "deprecated",
+		},
+	}
+	current := v1alpha1.Release{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "v1.1.0",
+		},
+		Spec: v1alpha1.ReleaseSpec{
+			Apps: []v1alpha1.ReleaseSpecApp{
+				{Name: "cert-exporter", Version: "1.2.3"},
+				{Name: "coredns", ComponentVersion: "1.6.5", Version: "1.1.0"},
+			},
+			Components: []v1alpha1.ReleaseSpecComponent{
+				{Name: "kubernetes", Version: "1.19.8"},
+				{Name: "calico", Version: "3.15.1"},
+				{Name: "containerlinux", Version: "2605.6.0"},
+			},
+			Date:  timePtr(metav1.Date(2020, 8, 24, 12, 39, 32, 0, time.UTC)),
+			State: "active",
+		},
+	}
+
+	expected := `# Release v1.1.0
+
+- Date: 2020-08-24
+- State: active
+
+## Components
+
+| Name | Version |
+| --- | --- |
+| kubernetes | 1.19.8 |
+| calico | 3.15.1 |
+| containerlinux | 2605.6.0 |
+
+## Apps
+
+| Name | Version | Component version |
+| --- | --- | --- |
+| cert-exporter | 1.2.3 |  |
+| coredns | 1.1.0 | 1.6.5 |
+
+## Changes since v1.0.0
+
+### Components
+
+- Updated kubernetes from 1.18.9 to 1.19.8
+- Added containerlinux 2605.6.0
+- Removed etcd 3.4.9
+`
+	if diff := cmp.Diff(RenderReleaseReport(current, previous), expected); diff != "" {
+		t.Fatal(diff)
+	}
+
+	// The diff must reproduce the current release when applied to the previous one.
+	patch, err := Diff(previous, current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, patched := Apply(previous, patch)
+	if diff := cmp.Diff(patched.Spec.Components, current.Spec.Components); diff != "" {
+		t.Error(diff)
+	}
+	if diff := cmp.Diff(patched.Spec.Apps, current.Spec.Apps); diff != "" {
+		t.Error(diff)
+	}
+}
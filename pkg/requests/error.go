@@ -0,0 +1,12 @@
+package requests
+
+import "github.com/giantswarm/microerror"
+
+var invalidRequestsError = &microerror.Error{
+	Kind: "invalidRequestsError",
+}
+
+// IsInvalidRequests asserts invalidRequestsError.
+func IsInvalidRequests(err error) bool {
+	return microerror.Cause(err) == invalidRequestsError
+}
@@ -2,69 +2,838 @@ package requests
 
 import (
 	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/giantswarm/apiextensions/v2/pkg/apis/release/v1alpha1"
 	"github.com/giantswarm/microerror"
 	"sigs.k8s.io/yaml"
+
+	"github.com/giantswarm/releaseclient/pkg/filesystem"
+	"github.com/giantswarm/releaseclient/pkg/key"
 )
 
+// Requests holds the requested component and app versions of a requests file. Methods which
+// only read the requests, like Check, CheckAll or Validate, are safe for concurrent use. Load,
+// LoadWithOverlay, Canonicalize and RemoveException modify the requests and must not be
+// called concurrently with any other method.
 type Requests struct {
+	// baseline requests apply to every active release, independently of release patterns.
+	baseline []versionRequest
+	// defaults apply instead of the pattern requests to releases matching none of the
+	// release patterns.
+	defaults []versionRequest
 	requests []releaseRequest
 }
 
-func (r Requests) Load(data []byte) error {
+func (r *Requests) Load(data []byte) error {
 	var file requestsFile
 	err := yaml.UnmarshalStrict(data, &file)
 	if err != nil {
 		return microerror.Mask(err)
 	}
+	r.baseline = file.Baseline
+	r.defaults = file.Defaults
 	r.requests = file.Releases
 	return nil
 }
 
-func (r Requests) Check(release v1alpha1.Release) error {
-	// Check that all active releases contain all requested component versions.
-	if release.Spec.State == "active" {
-		requests, err := findMatchingRequests(release.Name, r.requests)
+// LoadWithOverlay loads the base requests and merges the overlay onto them. Overlay requests
+// replace base requests for the same release pattern and component name, all other overlay
+// requests and patterns are added.
+func (r *Requests) LoadWithOverlay(base []byte, overlay []byte) error {
+	var baseFile requestsFile
+	err := yaml.UnmarshalStrict(base, &baseFile)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	var overlayFile requestsFile
+	err = yaml.UnmarshalStrict(overlay, &overlayFile)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	r.baseline = mergeVersionRequests(baseFile.Baseline, overlayFile.Baseline)
+	r.defaults = mergeVersionRequests(baseFile.Defaults, overlayFile.Defaults)
+	r.requests = mergeReleaseRequests(baseFile.Releases, overlayFile.Releases)
+	return nil
+}
+
+// LoadAllProviders reads and loads the requests file of each of the given providers. When a
+// requests file can't be parsed it returns an error matched by IsInvalidRequests naming the
+// provider.
+func LoadAllProviders(fs filesystem.Filesystem, providers []string) (map[string]Requests, error) {
+	all := map[string]Requests{}
+	for _, provider := range providers {
+		path := filepath.Join(provider, key.RequestsFilename)
+		data, err := fs.ReadFile(path)
 		if err != nil {
-			return microerror.Mask(err)
+			return nil, microerror.Mask(err)
+		}
+
+		var requests Requests
+		err = requests.Load(data)
+		if err != nil {
+			return nil, microerror.Maskf(invalidRequestsError, "requests of provider %s in %s can't be loaded: %s", provider, path, err)
+		}
+		all[provider] = requests
+	}
+
+	return all, nil
+}
+
+// Diff compares two sets of requests. Release patterns and components are matched by name,
+// exceptions by release pattern, component and excepted release. Baseline and default requests
+// are compared within their section. Results are ordered as in the requests before followed by
+// additions in the order of the requests after.
+func Diff(before Requests, after Requests) RequestsDiff {
+	var diff RequestsDiff
+
+	oldVersions, oldExceptions := indexRequests(before)
+	newVersions, newExceptions := indexRequests(after)
+
+	oldPatterns := map[string]bool{}
+	for _, release := range before.requests {
+		oldPatterns[release.Name] = true
+	}
+	newPatterns := map[string]bool{}
+	for _, release := range after.requests {
+		newPatterns[release.Name] = true
+	}
+
+	for _, release := range before.requests {
+		if !newPatterns[release.Name] {
+			diff.RemovedPatterns = append(diff.RemovedPatterns, release.Name)
+		}
+	}
+	for _, release := range after.requests {
+		if !oldPatterns[release.Name] {
+			diff.AddedPatterns = append(diff.AddedPatterns, release.Name)
+		}
+	}
+
+	for _, s := range before.sections() {
+		for _, request := range s.requests {
+			k := requestKey{section: s.name, pattern: s.pattern, component: request.Name}
+			if newVersion, ok := newVersions[k]; !ok || newVersion != request.Version {
+				diff.ChangedVersions = append(diff.ChangedVersions, VersionChange{
+					Component: request.Name,
+					Pattern:   s.pattern,
+					Section:   s.name,
+					Old:       request.Version,
+					New:       newVersion,
+				})
+			}
+		}
+	}
+	for _, s := range after.sections() {
+		for _, request := range s.requests {
+			k := requestKey{section: s.name, pattern: s.pattern, component: request.Name}
+			if _, ok := oldVersions[k]; !ok {
+				diff.ChangedVersions = append(diff.ChangedVersions, VersionChange{
+					Component: request.Name,
+					Pattern:   s.pattern,
+					Section:   s.name,
+					New:       request.Version,
+				})
+			}
+		}
+	}
+
+	for _, exception := range before.Exceptions() {
+		if !newExceptions[exceptionKey(exception)] {
+			diff.RemovedExceptions = append(diff.RemovedExceptions, exception)
+		}
+	}
+	for _, exception := range after.Exceptions() {
+		if !oldExceptions[exceptionKey(exception)] {
+			diff.AddedExceptions = append(diff.AddedExceptions, exception)
+		}
+	}
+
+	return diff
+}
+
+// requestKey identifies a request within a set of requests.
+type requestKey struct {
+	section   string
+	pattern   string
+	component string
+	release   string
+}
+
+func exceptionKey(exception ExceptionReport) requestKey {
+	return requestKey{
+		section:   exception.Section,
+		pattern:   exception.Pattern,
+		component: exception.Component,
+		release:   exception.Release,
+	}
+}
+
+// indexRequests returns the requested versions and exceptions of the given requests by key.
+func indexRequests(r Requests) (map[requestKey]string, map[requestKey]bool) {
+	versions := map[requestKey]string{}
+	for _, s := range r.sections() {
+		for _, request := range s.requests {
+			versions[requestKey{section: s.name, pattern: s.pattern, component: request.Name}] = request.Version
+		}
+	}
+
+	exceptions := map[requestKey]bool{}
+	for _, exception := range r.Exceptions() {
+		exceptions[exceptionKey(exception)] = true
+	}
+
+	return versions, exceptions
+}
+
+// requestSection is a list of requests of the requests file, see sections.
+type requestSection struct {
+	// name is "baseline" or "defaults" for the requests outside of release patterns and empty
+	// for the requests of a release pattern.
+	name     string
+	pattern  string
+	requests []versionRequest
+}
+
+// sections returns the baseline and default requests followed by the requests of every
+// release pattern in file order.
+func (r Requests) sections() []requestSection {
+	sections := []requestSection{{name: "baseline", requests: r.baseline}, {name: "defaults", requests: r.defaults}}
+	for _, release := range r.requests {
+		sections = append(sections, requestSection{pattern: release.Name, requests: release.Requests})
+	}
+	return sections
+}
+
+// Patterns returns the release patterns of the loaded requests in file order.
+func (r Requests) Patterns() []string {
+	var patterns []string
+	for _, release := range r.requests {
+		patterns = append(patterns, release.Name)
+	}
+	return patterns
+}
+
+// Summary returns a short overview of the loaded requests for display, one count per line.
+// Baseline and default requests are counted as requests.
+func (r Requests) Summary() string {
+	var requests, exceptions int
+	for _, s := range r.sections() {
+		requests += len(s.requests)
+		for _, request := range s.requests {
+			exceptions += len(request.Exceptions)
 		}
+	}
+
+	return fmt.Sprintf("release patterns: %d\nrequests: %d\nexceptions: %d\n", len(r.requests), requests, exceptions)
+}
+
+// Validate checks the structure of the loaded requests independently of any release: release
+// patterns and requested versions must be valid semver constraints, exception versions must
+// be valid semver within the release pattern they are listed under and names must not be
+// empty. Options like WithDisallowedOperators enable additional policy checks.
+func (r Requests) Validate(opts ...ValidateOption) error {
+	var o validateOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
 
-		var unsatisfiedRequests []string
-		for _, request := range requests {
-			componentsSatisfied, actualComponentVersion, err := componentListSatisfiesRequest(request, release.Spec.Components)
+	var patterns int
+	for _, section := range r.sections() {
+		where := section.name
+		if section.name == "" {
+			if section.pattern == "" {
+				return microerror.Maskf(invalidRequestsError, "release pattern %d must not be empty", patterns)
+			}
+			patterns++
+			_, err := semver.NewConstraint(section.pattern)
 			if err != nil {
-				return microerror.Mask(err)
+				return microerror.Maskf(invalidRequestsError, "release pattern %s must be a valid semver constraint: %s", section.pattern, err)
 			}
+			where = "releases " + section.pattern
+		}
 
-			appsSatisfied, actualAppVersion, err := appListSatisfiesRequest(request, release.Spec.Apps)
+		for j, request := range section.requests {
+			if request.Name == "" {
+				if section.name != "" {
+					return microerror.Maskf(invalidRequestsError, "name of %s request %d must not be empty", section.name, j)
+				}
+				return microerror.Maskf(invalidRequestsError, "name of request %d for releases %s must not be empty", j, section.pattern)
+			}
+			_, err := semver.NewConstraint(request.Version)
+			if err != nil {
+				return microerror.Maskf(invalidRequestsError, "version %s requested for %s in %s must be a valid semver constraint: %s", request.Version, request.Name, where, err)
+			}
+			satisfiable, err := constraintSatisfiable(request.Version)
 			if err != nil {
 				return microerror.Mask(err)
 			}
+			if !satisfiable {
+				return microerror.Maskf(invalidRequestsError, "version %s requested for %s in %s can never be satisfied", request.Version, request.Name, where)
+			}
+			operator := disallowedOperator(request.Version, o.disallowedOperators)
+			if operator != "" {
+				return microerror.Maskf(invalidRequestsError, "version %s requested for %s in %s uses disallowed operator %s", request.Version, request.Name, where, operator)
+			}
+			if o.issueReferences && !validIssueReference(request.Issue) {
+				return microerror.Maskf(invalidRequestsError, "issue %q for %s in %s must be an https URL or an issue reference like #123 or org/repo#123", request.Issue, request.Name, where)
+			}
 
-			if !componentsSatisfied && !appsSatisfied {
-				// Either components or apps were not satisfied. Use the 'actual' version which isn't empty.
-				actual := actualComponentVersion
-				if actual == "" {
-					actual = actualAppVersion
+			for _, exception := range request.Exceptions {
+				_, err = semver.NewVersion(exception.Version)
+				if err != nil {
+					return microerror.Maskf(invalidRequestsError, "exception release version %s for %s in %s must be valid semver: %s", exception.Version, request.Name, where, err)
+				}
+				if section.name != "" {
+					continue
 				}
+				match, err := versionMatches(exception.Version, section.pattern)
+				if err != nil {
+					return microerror.Mask(err)
+				}
+				if !match {
+					return microerror.Maskf(invalidRequestsError, "exception release version %s for %s must match releases %s, otherwise it excludes nothing", exception.Version, request.Name, section.pattern)
+				}
+			}
+		}
+	}
+
+	err := r.validatePrefixes()
+	if err != nil {
+		return microerror.Mask(err)
+	}
 
-				unsatisfied := fmt.Sprintf("requested: %s: %s \tactual: %s", request.Name, request.Version, actual)
-				unsatisfiedRequests = append(unsatisfiedRequests, unsatisfied)
+	return nil
+}
+
+// validatePrefixes checks that release patterns and exception release versions consistently
+// either use or omit the `v` prefix, all of them following the same convention.
+func (r Requests) validatePrefixes() error {
+	var convention prefixConvention
+	for _, section := range r.sections() {
+		if section.pattern != "" {
+			err := convention.add("release pattern", section.pattern)
+			if err != nil {
+				return microerror.Mask(err)
 			}
 		}
+		for _, request := range section.requests {
+			for _, exception := range request.Exceptions {
+				err := convention.add("exception release version", exception.Version)
+				if err != nil {
+					return microerror.Mask(err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
 
-		if len(unsatisfiedRequests) > 0 {
-			msg := fmt.Sprintf("Release %s does not meet the requested version requirements:\n%s", release.Name, strings.Join(unsatisfiedRequests, ",\n"))
-			return microerror.Mask(fmt.Errorf(msg))
+// prefixConvention remembers the first version seen with and without a `v` prefix, along
+// with what kind of version it is.
+type prefixConvention struct {
+	prefixed       string
+	prefixedKind   string
+	unprefixed     string
+	unprefixedKind string
+}
+
+func (c *prefixConvention) add(kind string, constraint string) error {
+	for _, match := range constraintBoundPattern.FindAllStringSubmatch(constraint, -1) {
+		if strings.Contains(strings.TrimSuffix(match[0], match[2]), "v") {
+			if c.prefixed == "" {
+				c.prefixed, c.prefixedKind = constraint, kind
+			}
+		} else {
+			if c.unprefixed == "" {
+				c.unprefixed, c.unprefixedKind = constraint, kind
+			}
+		}
+		if c.prefixed != "" && c.unprefixed != "" {
+			return microerror.Maskf(invalidRequestsError, "%s %s uses a v prefix but %s %s doesn't, use one convention for all of them", c.prefixedKind, c.prefixed, c.unprefixedKind, c.unprefixed)
+		}
+	}
+	return nil
+}
+
+// Canonicalize rewrites all release patterns and requested versions, including those of the
+// baseline and defaults, into canonical form, see NonCanonicalConstraints.
+func (r *Requests) Canonicalize() error {
+	for i, release := range r.requests {
+		pattern, err := canonicalConstraint(release.Name)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		r.requests[i].Name = pattern
+	}
+
+	// The sections share their requests with r, so versions are rewritten in place.
+	for _, s := range r.sections() {
+		for j, request := range s.requests {
+			version, err := canonicalConstraint(request.Version)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+			s.requests[j].Version = version
 		}
 	}
 
 	return nil
 }
 
+// NonCanonicalConstraints returns the release patterns and requested versions which aren't
+// written in canonical form, those of the baseline and defaults first. Canonical constraints
+// have no space between operator and version, separate AND conditions with a single space and
+// OR conditions with " || ", e.g. ">=1.2.0 <2.0.0 || >=3.0.0".
+func (r Requests) NonCanonicalConstraints() ([]NonCanonicalConstraint, error) {
+	var nonCanonical []NonCanonicalConstraint
+	for _, s := range r.sections() {
+		if s.name == "" {
+			pattern, err := canonicalConstraint(s.pattern)
+			if err != nil {
+				return nil, microerror.Mask(err)
+			}
+			if pattern != s.pattern {
+				nonCanonical = append(nonCanonical, NonCanonicalConstraint{
+					Constraint: s.pattern,
+					Canonical:  pattern,
+					Pattern:    s.pattern,
+				})
+			}
+		}
+
+		for _, request := range s.requests {
+			version, err := canonicalConstraint(request.Version)
+			if err != nil {
+				return nil, microerror.Mask(err)
+			}
+			if version != request.Version {
+				nonCanonical = append(nonCanonical, NonCanonicalConstraint{
+					Component:  request.Name,
+					Constraint: request.Version,
+					Canonical:  version,
+					Pattern:    s.pattern,
+					Section:    s.name,
+				})
+			}
+		}
+	}
+
+	return nonCanonical, nil
+}
+
+// Sort orders the release patterns by the lowest release version they match, patterns without
+// a lower bound first and ties broken by the pattern itself, see UnsortedPatterns.
+func (r *Requests) Sort() error {
+	minimums, err := patternMinimums(r.requests)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	sort.SliceStable(r.requests, func(i, j int) bool {
+		return patternLess(r.requests[i].Name, r.requests[j].Name, minimums)
+	})
+
+	return nil
+}
+
+// UnsortedPatterns returns the release patterns which, in file order, follow a pattern that
+// Sort would place after them.
+func (r Requests) UnsortedPatterns() ([]string, error) {
+	minimums, err := patternMinimums(r.requests)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	var unsorted []string
+	for i := 1; i < len(r.requests); i++ {
+		if patternLess(r.requests[i].Name, r.requests[i-1].Name, minimums) {
+			unsorted = append(unsorted, r.requests[i].Name)
+		}
+	}
+
+	return unsorted, nil
+}
+
+// Exceptions returns every exception in the requests, those of the baseline and defaults
+// first, in file order.
+func (r Requests) Exceptions() []ExceptionReport {
+	var reports []ExceptionReport
+	for _, s := range r.sections() {
+		for _, request := range s.requests {
+			for _, exception := range request.Exceptions {
+				reports = append(reports, ExceptionReport{
+					Component: request.Name,
+					Issue:     request.Issue,
+					Pattern:   s.pattern,
+					Reason:    exception.Reason,
+					Release:   exception.Version,
+					Section:   s.name,
+					Version:   request.Version,
+				})
+			}
+		}
+	}
+	return reports
+}
+
+// FilterByComponent returns every request for the component or app with the given name, those
+// of the baseline and defaults first, followed by the ones of every release pattern in file
+// order.
+func (r Requests) FilterByComponent(name string) []VersionRequestWithPattern {
+	var filtered []VersionRequestWithPattern
+	for _, s := range r.sections() {
+		for _, request := range s.requests {
+			if request.Name != name {
+				continue
+			}
+
+			var exceptions []string
+			for _, exception := range request.Exceptions {
+				exceptions = append(exceptions, exception.Version)
+			}
+			filtered = append(filtered, VersionRequestWithPattern{
+				Component:  request.Name,
+				Exceptions: exceptions,
+				Issue:      request.Issue,
+				Pattern:    s.pattern,
+				Version:    request.Version,
+			})
+		}
+	}
+
+	return filtered
+}
+
+// RemoveException removes the exception for the given release version from the request for
+// the component under the given release pattern, or from the baseline and default requests for
+// an empty pattern. It returns whether an exception was removed.
+func (r *Requests) RemoveException(pattern string, component string, releaseVersion string) bool {
+	for _, s := range r.sections() {
+		if s.pattern != pattern {
+			continue
+		}
+		for j, request := range s.requests {
+			if request.Name != component {
+				continue
+			}
+			for k, exception := range request.Exceptions {
+				if exception.Version == releaseVersion {
+					exceptions := append([]requestException(nil), request.Exceptions[:k]...)
+					s.requests[j].Exceptions = append(exceptions, request.Exceptions[k+1:]...)
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+func (r Requests) Check(release v1alpha1.Release) error {
+	unsatisfied, err := r.checkedUnsatisfied(release)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	var unsatisfiedRequests []string
+	for _, u := range unsatisfied {
+		message := fmt.Sprintf("requested: %s: %s \tactual: %s", u.Component, u.Constraint, u.Actual)
+		if miss := describeMiss(u.Actual, u.Constraint); miss != "" {
+			message += fmt.Sprintf(" (%s)", miss)
+		}
+		unsatisfiedRequests = append(unsatisfiedRequests, message)
+	}
+
+	if len(unsatisfiedRequests) > 0 {
+		msg := fmt.Sprintf("Release %s does not meet the requested version requirements:\n%s", release.Name, strings.Join(unsatisfiedRequests, ",\n"))
+		return microerror.Mask(fmt.Errorf(msg))
+	}
+
+	return nil
+}
+
+// checkedUnsatisfied returns the requests Check reports for the release. Only active releases
+// must contain all requested component versions.
+func (r Requests) checkedUnsatisfied(release v1alpha1.Release) ([]UnsatisfiedRequest, error) {
+	if release.Spec.State != v1alpha1.StateActive {
+		return nil, nil
+	}
+
+	unsatisfied, err := r.findUnsatisfied(release)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	return unsatisfied, nil
+}
+
+// CheckAll checks every given release like Check. By default it returns the error of the first
+// release violating its requests, with WithCollectAll it checks all releases and returns a
+// single error describing every violation.
+func (r Requests) CheckAll(releases []v1alpha1.Release, opts ...CheckOption) error {
+	var o checkOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var violations []string
+	for _, release := range releases {
+		err := r.Check(release)
+		if err != nil {
+			if !o.collectAll {
+				return microerror.Mask(err)
+			}
+			violations = append(violations, microerror.Cause(err).Error())
+		}
+	}
+
+	if len(violations) > 0 {
+		return microerror.Mask(fmt.Errorf("%d releases do not meet the requested version requirements:\n%s", len(violations), strings.Join(violations, "\n")))
+	}
+
+	return nil
+}
+
+// IsSatisfied returns whether Check finds no unsatisfied request for the release. Like Check
+// it only evaluates active releases.
+func (r Requests) IsSatisfied(release v1alpha1.Release) (bool, error) {
+	unsatisfied, err := r.checkedUnsatisfied(release)
+	if err != nil {
+		return false, microerror.Mask(err)
+	}
+
+	return len(unsatisfied) == 0, nil
+}
+
+// CheckVersions returns the requests which a release with the given name shipping the given
+// component and app versions wouldn't satisfy, e.g. to simulate an upgrade. Components map
+// names to versions. Like for active releases, baseline requests apply.
+func (r Requests) CheckVersions(releaseName string, components map[string]string) ([]UnsatisfiedRequest, error) {
+	release := v1alpha1.Release{}
+	release.Name = releaseName
+	for name, version := range components {
+		release.Spec.Components = append(release.Spec.Components, v1alpha1.ReleaseSpecComponent{
+			Name:    name,
+			Version: version,
+		})
+	}
+
+	unsatisfied, err := r.findUnsatisfied(release)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return unsatisfied, nil
+}
+
+// SuggestFixes returns, for every request the given active release doesn't satisfy, the
+// minimum version the component or app would have to be bumped to.
+func (r Requests) SuggestFixes(release v1alpha1.Release) ([]Fix, error) {
+	if release.Spec.State != v1alpha1.StateActive {
+		return nil, nil
+	}
+
+	unsatisfied, err := r.findUnsatisfied(release)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	var fixes []Fix
+	for _, u := range unsatisfied {
+		fix := Fix{
+			Component:  u.Component,
+			Constraint: u.Constraint,
+			Current:    u.Actual,
+		}
+		minimum, err := constraintMinimum(u.Constraint)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		if minimum != nil {
+			fix.Minimum = minimum.String()
+		}
+		fixes = append(fixes, fix)
+	}
+
+	return fixes, nil
+}
+
+// MissingComponents returns the sorted names of the components requested for the given
+// release which it ships neither as a component nor as an app, regardless of version.
+func (r Requests) MissingComponents(release v1alpha1.Release) ([]string, error) {
+	requests, err := r.applicableRequests(release.Name)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	shipped := map[string]bool{}
+	for _, component := range release.Spec.Components {
+		shipped[component.Name] = true
+	}
+	for _, app := range release.Spec.Apps {
+		shipped[app.Name] = true
+	}
+
+	var missing []string
+	for _, request := range requests {
+		if !shipped[request.Name] {
+			missing = append(missing, request.Name)
+			// Report every name once even if several requests mention it.
+			shipped[request.Name] = true
+		}
+	}
+	sort.Strings(missing)
+
+	return missing, nil
+}
+
+// UnusedExceptions returns the exceptions whose excepted release, looked up in the given
+// releases, satisfies the request anyway, so that the exception has no effect anymore and can
+// be removed. Exceptions of baseline and default requests are reported with an empty Pattern.
+// Exceptions for releases which aren't given are never reported.
+func (r Requests) UnusedExceptions(releases []v1alpha1.Release) ([]ExceptionReport, error) {
+	var reports []ExceptionReport
+	for _, s := range r.sections() {
+		for _, request := range s.requests {
+			for _, exception := range request.Exceptions {
+				for _, release := range releases {
+					match, err := versionMatches(release.Name, exception.Version)
+					if err != nil {
+						return nil, microerror.Mask(err)
+					}
+					if !match {
+						continue
+					}
+
+					componentsSatisfied, _, err := componentListSatisfiesRequest(request, release.Spec.Components)
+					if err != nil {
+						return nil, microerror.Mask(err)
+					}
+					appsSatisfied, _, err := appListSatisfiesRequest(request, release.Spec.Apps)
+					if err != nil {
+						return nil, microerror.Mask(err)
+					}
+					if componentsSatisfied || appsSatisfied {
+						reports = append(reports, ExceptionReport{
+							Component: request.Name,
+							Issue:     request.Issue,
+							Pattern:   s.pattern,
+							Reason:    exception.Reason,
+							Release:   exception.Version,
+							Section:   s.name,
+							Version:   request.Version,
+						})
+					}
+					break
+				}
+			}
+		}
+	}
+
+	return reports, nil
+}
+
+// applicableRequests returns the baseline requests the given release isn't excepted from
+// followed by the requests of the release patterns it matches or, if it matches none, the
+// default requests it isn't excepted from.
+func (r Requests) applicableRequests(releaseName string) ([]versionRequest, error) {
+	requests, err := filterExcepted(releaseName, r.baseline)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	var matchesPattern bool
+	for _, release := range r.requests {
+		match, err := versionMatches(releaseName, release.Name)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		if match {
+			matchesPattern = true
+			break
+		}
+	}
+
+	if !matchesPattern {
+		defaults, err := filterExcepted(releaseName, r.defaults)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		return append(requests, defaults...), nil
+	}
+
+	matching, err := findMatchingRequests(releaseName, r.requests)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	requests = append(requests, matching...)
+
+	return requests, nil
+}
+
+// filterExcepted returns the requests the given release isn't excepted from.
+func filterExcepted(releaseName string, requests []versionRequest) ([]versionRequest, error) {
+	var filtered []versionRequest
+	for _, request := range requests {
+		excepted, err := isExcepted(releaseName, request)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		if !excepted {
+			filtered = append(filtered, request)
+		}
+	}
+	return filtered, nil
+}
+
+// findUnsatisfied returns the requests matching the given release which neither its components
+// nor its apps satisfy.
+func (r Requests) findUnsatisfied(release v1alpha1.Release) ([]UnsatisfiedRequest, error) {
+	requests, err := r.applicableRequests(release.Name)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	var unsatisfied []UnsatisfiedRequest
+	for _, request := range requests {
+		componentsSatisfied, actualComponentVersion, err := componentListSatisfiesRequest(request, release.Spec.Components)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		appsSatisfied, actualAppVersion, err := appListSatisfiesRequest(request, release.Spec.Apps)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		if !componentsSatisfied && !appsSatisfied {
+			// Either components or apps were not satisfied. Use the 'actual' version which isn't empty.
+			actual := actualComponentVersion
+			if actual == "" {
+				actual = actualAppVersion
+			}
+
+			unsatisfied = append(unsatisfied, UnsatisfiedRequest{
+				Actual:     actual,
+				Component:  request.Name,
+				Constraint: request.Version,
+				Issue:      request.Issue,
+			})
+		}
+	}
+
+	return unsatisfied, nil
+}
+
 // appListSatisfiesRequest determines whether the given request is satisfied in the given app list.
 // It returns a boolean value for whether the request is satisfied as well as
 // a string containing the actual app version which satisfies the request.
@@ -111,6 +880,55 @@ func componentListSatisfiesRequest(request versionRequest, componentList []v1alp
 	return false, actual, nil
 }
 
+// mergeReleaseRequests returns the base release requests with the overlay applied per
+// release pattern and component name.
+func mergeReleaseRequests(base []releaseRequest, overlay []releaseRequest) []releaseRequest {
+	merged := make([]releaseRequest, 0, len(base)+len(overlay))
+	for _, release := range base {
+		release.Requests = append([]versionRequest(nil), release.Requests...)
+		merged = append(merged, release)
+	}
+
+	for _, overlayRelease := range overlay {
+		index := -1
+		for i, release := range merged {
+			if release.Name == overlayRelease.Name {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			merged = append(merged, overlayRelease)
+			continue
+		}
+
+		merged[index].Requests = mergeVersionRequests(merged[index].Requests, overlayRelease.Requests)
+	}
+
+	return merged
+}
+
+// mergeVersionRequests returns the base requests with overlay requests replacing those for the
+// same component name. All other overlay requests are appended.
+func mergeVersionRequests(base []versionRequest, overlay []versionRequest) []versionRequest {
+	merged := append([]versionRequest(nil), base...)
+	for _, overlayRequest := range overlay {
+		replaced := false
+		for j, request := range merged {
+			if request.Name == overlayRequest.Name {
+				merged[j] = overlayRequest
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged = append(merged, overlayRequest)
+		}
+	}
+
+	return merged
+}
+
 // findMatchingRequests searches the given array of releaseRequests
 // for requests which apply to the given release version.
 func findMatchingRequests(release string, requests []releaseRequest) ([]versionRequest, error) {
@@ -125,15 +943,9 @@ func findMatchingRequests(release string, requests []releaseRequest) ([]versionR
 
 		if match {
 			for _, component := range request.Requests {
-				releaseIsExcluded := false
-				if component.Exceptions != nil {
-					// Check the excluded releases for this component to see if our release is there.
-					for _, e := range component.Exceptions {
-						releaseIsExcluded, err = versionMatches(e.Version, request.Name)
-						if err != nil {
-							return nil, microerror.Mask(err)
-						}
-					}
+				releaseIsExcluded, err := isExcepted(release, component)
+				if err != nil {
+					return nil, microerror.Mask(err)
 				}
 
 				if !releaseIsExcluded {
@@ -145,10 +957,359 @@ func findMatchingRequests(release string, requests []releaseRequest) ([]versionR
 	return requestList, nil
 }
 
+// canonicalConstraint returns the canonical form of the given semver constraint.
+func canonicalConstraint(constraint string) (string, error) {
+	c, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return "", microerror.Maskf(invalidRequestsError, "%s must be a valid semver constraint: %s", constraint, err)
+	}
+	return c.String(), nil
+}
+
+// describeMiss classifies how far the actual version is below the minimum version satisfying
+// the constraint, e.g. "close miss: 1 patch behind 1.5.0". It returns an empty string when the
+// distance can't be determined or the actual version isn't below the minimum.
+func describeMiss(actual string, constraint string) string {
+	actualVersion, err := semver.NewVersion(actual)
+	if err != nil {
+		return ""
+	}
+	minimum, err := constraintMinimum(constraint)
+	if err != nil || minimum == nil || !actualVersion.LessThan(minimum) {
+		return ""
+	}
+
+	switch {
+	case actualVersion.Major() < minimum.Major():
+		return fmt.Sprintf("%d major behind %s", minimum.Major()-actualVersion.Major(), minimum)
+	case actualVersion.Minor() < minimum.Minor():
+		return fmt.Sprintf("%d minor behind %s", minimum.Minor()-actualVersion.Minor(), minimum)
+	case actualVersion.Patch() < minimum.Patch():
+		return fmt.Sprintf("close miss: %d patch behind %s", minimum.Patch()-actualVersion.Patch(), minimum)
+	default:
+		return fmt.Sprintf("close miss: pre-release of %s", minimum)
+	}
+}
+
+// constraintBoundPattern matches a single constraint term like ">= 1.2.0", "~1.2" or "1.2.x".
+var constraintBoundPattern = regexp.MustCompile(`(>=|=>|<=|=<|!=|>|<|=|~>|~|\^)?\s*v?([0-9xX*]+(\.[0-9xX*]+)?(\.[0-9xX*]+)?(-[0-9A-Za-z.-]+)?(\+[0-9A-Za-z.-]+)?)`)
+
+// hyphenRangePattern matches hyphen ranges like "1.2.0 - 1.4.0".
+var hyphenRangePattern = regexp.MustCompile(`v?([0-9xX*]+(\.[0-9xX*]+)?(\.[0-9xX*]+)?(-[0-9A-Za-z.-]+)?(\+[0-9A-Za-z.-]+)?)\s+-\s+v?([0-9xX*]+(\.[0-9xX*]+)?(\.[0-9xX*]+)?(-[0-9A-Za-z.-]+)?(\+[0-9A-Za-z.-]+)?)`)
+
+// versionBound is the lower or upper bound of a constraint condition.
+type versionBound struct {
+	version   *semver.Version
+	inclusive bool
+}
+
+// conditionBounds holds the bounds of an AND condition of a constraint. A nil bound means the
+// condition is unbounded in that direction.
+type conditionBounds struct {
+	lower *versionBound
+	upper *versionBound
+}
+
+// constraintBounds returns the bounds of every OR condition of the given constraint following
+// the semantics of semver.Constraints, e.g. ">1.2" is ">=1.3.0" and "~1.2.3" is
+// ">=1.2.3 <1.3.0". Exclusions like "!=1.2.0" don't affect the bounds.
+func constraintBounds(constraint string) ([]conditionBounds, error) {
+	_, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	var conditions []conditionBounds
+	for _, or := range strings.Split(hyphenRangePattern.ReplaceAllString(constraint, ">=$1 <=$6"), "||") {
+		var condition conditionBounds
+		for _, match := range constraintBoundPattern.FindAllStringSubmatch(or, -1) {
+			lower, upper, err := termBounds(match[1], match[2])
+			if err != nil {
+				return nil, microerror.Mask(err)
+			}
+			if lower != nil && (condition.lower == nil || boundStricter(lower, condition.lower, 1)) {
+				condition.lower = lower
+			}
+			if upper != nil && (condition.upper == nil || boundStricter(upper, condition.upper, -1)) {
+				condition.upper = upper
+			}
+		}
+		conditions = append(conditions, condition)
+	}
+
+	return conditions, nil
+}
+
+// termBounds returns the bounds of a single constraint term like ">= 1.2" given its operator
+// and version.
+func termBounds(operator string, version string) (*versionBound, *versionBound, error) {
+	// Count the segments written before the first wildcard, e.g. 2 for 1.2 and 1.2.x.
+	var written int
+	for _, part := range strings.Split(strings.SplitN(strings.SplitN(version, "+", 2)[0], "-", 2)[0], ".") {
+		if part == "x" || part == "X" || part == "*" {
+			break
+		}
+		written++
+	}
+	if written == 0 {
+		// Wildcards like "*" match every version.
+		return nil, nil, nil
+	}
+
+	base, err := semver.NewVersion(wildcardsToZero(version))
+	if err != nil {
+		return nil, nil, microerror.Mask(err)
+	}
+	// next is the first version beyond the segments written, e.g. 1.3.0 for 1.2.
+	next := func(segments int) *versionBound {
+		var v semver.Version
+		switch segments {
+		case 1:
+			v = base.IncMajor()
+		case 2:
+			v = base.IncMinor()
+		default:
+			v = base.IncPatch()
+		}
+		return &versionBound{version: &v}
+	}
+	inclusive := &versionBound{version: base, inclusive: true}
+
+	switch operator {
+	case ">":
+		if written < 3 {
+			lower := next(written)
+			lower.inclusive = true
+			return lower, nil, nil
+		}
+		return &versionBound{version: base}, nil, nil
+	case ">=", "=>":
+		return inclusive, nil, nil
+	case "<":
+		return nil, &versionBound{version: base}, nil
+	case "<=", "=<":
+		if written < 3 {
+			return nil, next(written), nil
+		}
+		return nil, inclusive, nil
+	case "", "=":
+		if written < 3 {
+			return inclusive, next(written), nil
+		}
+		return inclusive, inclusive, nil
+	case "~", "~>":
+		if written == 1 {
+			return inclusive, next(1), nil
+		}
+		return inclusive, next(2), nil
+	case "^":
+		switch {
+		case base.Major() > 0 || written == 1:
+			return inclusive, next(1), nil
+		case base.Minor() > 0 || written == 2:
+			return inclusive, next(2), nil
+		default:
+			return inclusive, next(3), nil
+		}
+	}
+
+	// Exclusions like "!=1.2.0" leave the bounds unchanged.
+	return nil, nil, nil
+}
+
+// boundStricter returns whether bound a is stricter than bound b, where direction is 1 for
+// lower and -1 for upper bounds. Of two equal bounds the exclusive one is stricter.
+func boundStricter(a *versionBound, b *versionBound, direction int) bool {
+	c := a.version.Compare(b.version) * direction
+	return c > 0 || (c == 0 && !a.inclusive && b.inclusive)
+}
+
+// constraintMinimum returns the lowest version satisfying the given constraint as derived from
+// its lower bounds, or nil if the constraint has no lower bound. Across OR conditions the
+// lowest minimum is returned.
+func constraintMinimum(constraint string) (*semver.Version, error) {
+	conditions, err := constraintBounds(constraint)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	var minimum *semver.Version
+	for _, condition := range conditions {
+		if condition.lower == nil {
+			// This OR condition has no lower bound so neither does the constraint.
+			return nil, nil
+		}
+		conditionMinimum := condition.lower.version
+		if !condition.lower.inclusive {
+			next := conditionMinimum.IncPatch()
+			conditionMinimum = &next
+		}
+		if minimum == nil || conditionMinimum.LessThan(minimum) {
+			minimum = conditionMinimum
+		}
+	}
+
+	return minimum, nil
+}
+
+// patternMinimums returns the lowest release version matched by each of the given release
+// patterns, see constraintMinimum.
+func patternMinimums(requests []releaseRequest) (map[string]*semver.Version, error) {
+	minimums := map[string]*semver.Version{}
+	for _, release := range requests {
+		minimum, err := constraintMinimum(release.Name)
+		if err != nil {
+			return nil, microerror.Maskf(invalidRequestsError, "release pattern %s must be a valid semver constraint: %s", release.Name, err)
+		}
+		minimums[release.Name] = minimum
+	}
+
+	return minimums, nil
+}
+
+// patternLess returns whether release pattern a sorts before b given their minimums.
+func patternLess(a string, b string, minimums map[string]*semver.Version) bool {
+	minimumA, minimumB := minimums[a], minimums[b]
+	switch {
+	case minimumA == nil && minimumB != nil:
+		return true
+	case minimumA != nil && minimumB == nil:
+		return false
+	case minimumA != nil && !minimumA.Equal(minimumB):
+		return minimumA.LessThan(minimumB)
+	}
+
+	return a < b
+}
+
+// issueReferencePattern matches issue references like "#123" or "giantswarm/roadmap#123".
+var issueReferencePattern = regexp.MustCompile(`^([A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+)?#[0-9]+$`)
+
+// validIssueReference returns whether the given issue is empty, a full https URL or an issue
+// reference, see WithIssueReferences.
+func validIssueReference(issue string) bool {
+	if issue == "" || issueReferencePattern.MatchString(issue) {
+		return true
+	}
+
+	u, err := url.Parse(issue)
+	return err == nil && u.Scheme == "https" && u.Host != ""
+}
+
+// constraintSatisfiable returns whether any version can satisfy the given constraint, i.e.
+// whether an OR condition has a lower bound below its upper bound or both are the same
+// inclusive bound. Contradictory bounds like ">=2.0.0 <1.0.0" can never be satisfied.
+func constraintSatisfiable(constraint string) (bool, error) {
+	conditions, err := constraintBounds(constraint)
+	if err != nil {
+		return false, microerror.Mask(err)
+	}
+
+	for _, condition := range conditions {
+		if condition.lower == nil || condition.upper == nil {
+			return true, nil
+		}
+		c := condition.lower.version.Compare(condition.upper.version)
+		if c < 0 || (c == 0 && condition.lower.inclusive && condition.upper.inclusive) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// disallowedOperator returns the first operator of the given constraint which is listed in
+// disallowed, or an empty string if there is none. See WithDisallowedOperators for how terms
+// without an operator and aliases are treated.
+func disallowedOperator(constraint string, disallowed []string) string {
+	if len(disallowed) == 0 {
+		return ""
+	}
+
+	for _, or := range strings.Split(constraint, "||") {
+		hyphenRange := strings.Contains(or, " - ")
+		for _, match := range constraintBoundPattern.FindAllStringSubmatch(or, -1) {
+			operator, version := match[1], match[2]
+
+			switch operator {
+			case "":
+				// Bounds of hyphen ranges like "1.2.0 - 1.4.0" and partial versions aren't pins.
+				if hyphenRange || strings.Count(version, ".") < 2 || wildcardsToZero(version) != version {
+					continue
+				}
+				operator = "="
+			case "=>":
+				operator = ">="
+			case "=<":
+				operator = "<="
+			case "~>":
+				operator = "~"
+			}
+
+			for _, d := range disallowed {
+				if operator == d {
+					return operator
+				}
+			}
+		}
+	}
+
+	return ""
+}
+
+// wildcardsToZero replaces wildcard segments in a version like 1.2.x with zero.
+func wildcardsToZero(version string) string {
+	segments := strings.SplitN(version, "-", 2)
+	parts := strings.Split(segments[0], ".")
+	for i, part := range parts {
+		if part == "x" || part == "X" || part == "*" {
+			parts[i] = "0"
+		}
+	}
+	segments[0] = strings.Join(parts, ".")
+	return strings.Join(segments, "-")
+}
+
+// isExcepted returns whether the given release is listed in the exceptions of the request.
+func isExcepted(release string, request versionRequest) (bool, error) {
+	// Check the excluded releases for this component to see if our release is there.
+	for _, e := range request.Exceptions {
+		match, err := versionMatches(release, e.Version)
+		if err != nil {
+			return false, microerror.Mask(err)
+		}
+		if match {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// compiledConstraints caches parsed semver constraints by their string form, as every Check
+// evaluates the same release patterns and requested versions again. Parsed constraints are
+// never modified, so they can be shared between goroutines.
+var compiledConstraints sync.Map
+
+// compileConstraint returns the parsed form of the given semver constraint.
+func compileConstraint(constraint string) (*semver.Constraints, error) {
+	if c, ok := compiledConstraints.Load(constraint); ok {
+		return c.(*semver.Constraints), nil
+	}
+
+	c, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	compiledConstraints.Store(constraint, c)
+
+	return c, nil
+}
+
 // versionMatches compares the given version with the given semver
 // constraint pattern and returns whether it matches.
 func versionMatches(version string, pattern string) (bool, error) {
-	c, err := semver.NewConstraint(pattern)
+	c, err := compileConstraint(pattern)
 	if err != nil {
 		return false, fmt.Errorf("release names for requests must be valid semver constraints: %s", err)
 	}
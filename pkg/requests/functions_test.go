@@ -0,0 +1,1786 @@
+package requests
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/giantswarm/apiextensions/v2/pkg/apis/release/v1alpha1"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/giantswarm/releaseclient/pkg/filesystem"
+)
+
+func Test_Requests_Validate(t *testing.T) {
+	testCases := []struct {
+		name         string
+		requests     string
+		options      []ValidateOption
+		errorMatcher func(error) bool
+	}{
+		{
+			name: "case 0: valid requests",
+			requests: `releases:
+- name: ">= 11.0.0"
+  requests:
+  - name: kubernetes
+    version: ">= 1.16.0"
+    issue: https://github.com/giantswarm/roadmap/issues/1
+    except:
+    - releaseVersion: 11.0.1
+      reason: legacy
+`,
+		},
+		{
+			name: "case 1: invalid release pattern",
+			requests: `releases:
+- name: "> eleven"
+  requests:
+  - name: kubernetes
+    version: ">= 1.16.0"
+`,
+			errorMatcher: IsInvalidRequests,
+		},
+		{
+			name: "case 2: empty release pattern",
+			requests: `releases:
+- requests:
+  - name: kubernetes
+    version: ">= 1.16.0"
+`,
+			errorMatcher: IsInvalidRequests,
+		},
+		{
+			name: "case 3: invalid requested version",
+			requests: `releases:
+- name: ">= 11.0.0"
+  requests:
+  - name: kubernetes
+    version: "latest"
+`,
+			errorMatcher: IsInvalidRequests,
+		},
+		{
+			name: "case 4: empty request name",
+			requests: `releases:
+- name: ">= 11.0.0"
+  requests:
+  - version: ">= 1.16.0"
+`,
+			errorMatcher: IsInvalidRequests,
+		},
+		{
+			name: "case 5: invalid exception version",
+			requests: `releases:
+- name: ">= 11.0.0"
+  requests:
+  - name: kubernetes
+    version: ">= 1.16.0"
+    except:
+    - releaseVersion: ">= 11.0.1"
+      reason: legacy
+`,
+			errorMatcher: IsInvalidRequests,
+		},
+		{
+			name: "case 6: unsatisfiable requested version",
+			requests: `releases:
+- name: ">= 11.0.0"
+  requests:
+  - name: kubernetes
+    version: ">=2.0.0 <1.0.0"
+`,
+			errorMatcher: IsInvalidRequests,
+		},
+		{
+			name: "case 7: satisfiable alternative and exclusion",
+			requests: `releases:
+- name: ">= 11.0.0"
+  requests:
+  - name: kubernetes
+    version: ">=2.0.0 <1.0.0 || >=1.16.0 !=1.16.0"
+`,
+		},
+		{
+			name: "case 8: exception outside the release pattern",
+			requests: `releases:
+- name: ">= 11.0.0"
+  requests:
+  - name: kubernetes
+    version: ">= 1.16.0"
+    except:
+    - releaseVersion: 10.5.0
+      reason: legacy
+`,
+			errorMatcher: IsInvalidRequests,
+		},
+		{
+			name: "case 9: exception within the release pattern",
+			requests: `releases:
+- name: ">= 11.0.0"
+  requests:
+  - name: kubernetes
+    version: ">= 1.16.0"
+    except:
+    - releaseVersion: 11.0.1
+      reason: legacy
+`,
+		},
+		{
+			name: "case 10: exact pin allowed by default",
+			requests: `releases:
+- name: ">= 11.0.0"
+  requests:
+  - name: kubernetes
+    version: "=1.2.0"
+`,
+		},
+		{
+			name: "case 11: exact pin when exact pins are disallowed",
+			requests: `releases:
+- name: ">= 11.0.0"
+  requests:
+  - name: kubernetes
+    version: "=1.2.0"
+`,
+			options:      []ValidateOption{WithDisallowedOperators("=")},
+			errorMatcher: IsInvalidRequests,
+		},
+		{
+			name: "case 12: bare version in baseline when exact pins are disallowed",
+			requests: `baseline:
+- name: kubernetes
+  version: "1.2.0"
+releases: []
+`,
+			options:      []ValidateOption{WithDisallowedOperators("=")},
+			errorMatcher: IsInvalidRequests,
+		},
+		{
+			name: "case 13: ranges when exact pins are disallowed",
+			requests: `releases:
+- name: ">= 11.0.0"
+  requests:
+  - name: kubernetes
+    version: ">= 1.2.0, < 1.3.0 || 1.4.x || 1.5.0 - 1.6.0"
+`,
+			options: []ValidateOption{WithDisallowedOperators("=")},
+		},
+		{
+			name: "case 14: alias of a disallowed operator",
+			requests: `releases:
+- name: ">= 11.0.0"
+  requests:
+  - name: kubernetes
+    version: "~> 1.2.0"
+`,
+			options:      []ValidateOption{WithDisallowedOperators("~")},
+			errorMatcher: IsInvalidRequests,
+		},
+		{
+			name: "case 15: release patterns with mixed prefixes",
+			requests: `releases:
+- name: ">= 11.0.0"
+  requests:
+  - name: kubernetes
+    version: ">= 1.16.0"
+- name: ">= v12.0.0"
+  requests:
+  - name: kubernetes
+    version: ">= 1.17.0"
+`,
+			errorMatcher: IsInvalidRequests,
+		},
+		{
+			name: "case 16: exception versions with mixed prefixes",
+			requests: `baseline:
+- name: calico
+  version: ">= 3.10.0"
+  except:
+  - releaseVersion: 11.0.0
+releases:
+- name: ">= 11.0.0"
+  requests:
+  - name: kubernetes
+    version: ">= 1.16.0"
+    except:
+    - releaseVersion: v11.0.1
+`,
+			errorMatcher: IsInvalidRequests,
+		},
+		{
+			name: "case 17: consistent prefixes across patterns and exception versions",
+			requests: `releases:
+- name: ">= 11.0.0 < 12.0.0"
+  requests:
+  - name: kubernetes
+    version: ">= 1.16.0"
+    except:
+    - releaseVersion: 11.0.1
+    - releaseVersion: 11.0.2
+- name: ">= 12.0.0"
+  requests:
+  - name: kubernetes
+    version: ">= 1.17.0"
+`,
+		},
+		{
+			name: "case 18: linkable issues",
+			requests: `baseline:
+- name: calico
+  version: ">= 3.10.0"
+  issue: "#123"
+releases:
+- name: ">= 11.0.0"
+  requests:
+  - name: kubernetes
+    version: ">= 1.16.0"
+    issue: https://github.com/giantswarm/roadmap/issues/1
+  - name: coredns
+    version: ">= 1.6.0"
+    issue: giantswarm/roadmap#2
+  - name: etcd
+    version: ">= 3.4.0"
+`,
+			options: []ValidateOption{WithIssueReferences()},
+		},
+		{
+			name: "case 19: free-text issue",
+			requests: `releases:
+- name: ">= 11.0.0"
+  requests:
+  - name: kubernetes
+    version: ">= 1.16.0"
+    issue: see roadmap
+`,
+			options:      []ValidateOption{WithIssueReferences()},
+			errorMatcher: IsInvalidRequests,
+		},
+		{
+			name: "case 20: plain http issue URL in the baseline",
+			requests: `baseline:
+- name: calico
+  version: ">= 3.10.0"
+  issue: http://github.com/giantswarm/roadmap/issues/1
+releases: []
+`,
+			options:      []ValidateOption{WithIssueReferences()},
+			errorMatcher: IsInvalidRequests,
+		},
+		{
+			name: "case 21: free-text issue without the option",
+			requests: `releases:
+- name: ">= 11.0.0"
+  requests:
+  - name: kubernetes
+    version: ">= 1.16.0"
+    issue: see roadmap
+`,
+		},
+		{
+			name: "case 22: prefixed release pattern with unprefixed exception version",
+			requests: `releases:
+- name: ">= v11.0.0"
+  requests:
+  - name: kubernetes
+    version: ">= 1.16.0"
+    except:
+    - releaseVersion: 11.1.0
+`,
+			errorMatcher: IsInvalidRequests,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			var requests Requests
+			err := requests.Load([]byte(tc.requests))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = requests.Validate(tc.options...)
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// correct; carry on
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case !tc.errorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+		})
+	}
+}
+
+func Test_LoadAllProviders(t *testing.T) {
+	fs := filesystem.NewMemory(map[string]string{
+		"aws/requests.yaml": `releases:
+- name: ">=11.0.0"
+  requests:
+  - name: kubernetes
+    version: ">=1.16.0"
+`,
+		"azure/requests.yaml": "releases: []\n",
+		"kvm/requests.yaml":   "releases:\n- name: [\n",
+	})
+
+	all, err := LoadAllProviders(fs, []string{"aws", "azure"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(all["aws"].Patterns(), []string{">=11.0.0"}); diff != "" {
+		t.Error(diff)
+	}
+	if _, ok := all["azure"]; !ok || len(all) != 2 {
+		t.Errorf("expected requests for aws and azure, got %d providers", len(all))
+	}
+
+	_, err = LoadAllProviders(fs, []string{"aws", "kvm"})
+	if !IsInvalidRequests(err) {
+		t.Fatalf("expected invalid requests error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "requests of provider kvm in kvm/requests.yaml can't be loaded") {
+		t.Errorf("expected the error to name provider kvm, got %q", err.Error())
+	}
+}
+
+func Test_Requests_LoadWithOverlay(t *testing.T) {
+	base := `releases:
+- name: ">= 11.0.0"
+  requests:
+  - name: kubernetes
+    version: ">= 1.16.0"
+  - name: calico
+    version: ">= 3.10.0"
+`
+	overlay := `releases:
+- name: ">= 11.0.0"
+  requests:
+  - name: kubernetes
+    version: ">= 1.17.0"
+    issue: https://github.com/giantswarm/roadmap/issues/2
+  - name: coredns
+    version: ">= 1.6.0"
+- name: ">= 12.0.0"
+  requests:
+  - name: cert-exporter
+    version: ">= 1.3.0"
+`
+
+	var requests Requests
+	err := requests.LoadWithOverlay([]byte(base), []byte(overlay))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []releaseRequest{
+		{
+			Name: ">= 11.0.0",
+			Requests: []versionRequest{
+				{Name: "kubernetes", Version: ">= 1.17.0", Issue: "https://github.com/giantswarm/roadmap/issues/2"},
+				{Name: "calico", Version: ">= 3.10.0"},
+				{Name: "coredns", Version: ">= 1.6.0"},
+			},
+		},
+		{
+			Name: ">= 12.0.0",
+			Requests: []versionRequest{
+				{Name: "cert-exporter", Version: ">= 1.3.0"},
+			},
+		},
+	}
+	if diff := cmp.Diff(requests.requests, expected); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func Test_Diff(t *testing.T) {
+	before := `releases:
+- name: ">= 11.0.0"
+  requests:
+  - name: kubernetes
+    version: ">= 1.16.0"
+    except:
+    - releaseVersion: v11.0.1
+      reason: customer upgrade pending
+    - releaseVersion: v11.1.0
+      reason: blocked by calico
+  - name: calico
+    version: ">= 3.10.0"
+- name: ">= 10.0.0"
+  requests:
+  - name: etcd
+    version: ">= 3.3.0"
+`
+	after := `releases:
+- name: ">= 11.0.0"
+  requests:
+  - name: kubernetes
+    version: ">= 1.17.0"
+    except:
+    - releaseVersion: v11.1.0
+      reason: blocked by calico
+  - name: calico
+    version: ">= 3.10.0"
+- name: ">= 12.0.0"
+  requests:
+  - name: coredns
+    version: ">= 1.6.0"
+`
+
+	var beforeRequests, afterRequests Requests
+	err := beforeRequests.Load([]byte(before))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = afterRequests.Load([]byte(after))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := RequestsDiff{
+		AddedPatterns:   []string{">= 12.0.0"},
+		RemovedPatterns: []string{">= 10.0.0"},
+		ChangedVersions: []VersionChange{
+			{
+				Component: "kubernetes",
+				Pattern:   ">= 11.0.0",
+				Old:       ">= 1.16.0",
+				New:       ">= 1.17.0",
+			},
+			{
+				Component: "etcd",
+				Pattern:   ">= 10.0.0",
+				Old:       ">= 3.3.0",
+			},
+			{
+				Component: "coredns",
+				Pattern:   ">= 12.0.0",
+				New:       ">= 1.6.0",
+			},
+		},
+		RemovedExceptions: []ExceptionReport{
+			{
+				Component: "kubernetes",
+				Pattern:   ">= 11.0.0",
+				Reason:    "customer upgrade pending",
+				Release:   "v11.0.1",
+				Version:   ">= 1.16.0",
+			},
+		},
+	}
+	if diff := cmp.Diff(Diff(beforeRequests, afterRequests), expected); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func Test_Diff_BaselineAndDefaults(t *testing.T) {
+	before := `baseline:
+- name: cert-exporter
+  version: ">= 1.2.0"
+defaults:
+- name: kubernetes
+  version: ">= 1.16.0"
+releases: []
+`
+	after := `baseline:
+- name: cert-exporter
+  version: ">= 1.3.0"
+  except:
+  - releaseVersion: v11.0.0
+    reason: customer upgrade pending
+releases: []
+`
+
+	var beforeRequests, afterRequests Requests
+	err := beforeRequests.Load([]byte(before))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = afterRequests.Load([]byte(after))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := RequestsDiff{
+		ChangedVersions: []VersionChange{
+			{
+				Component: "cert-exporter",
+				Section:   "baseline",
+				Old:       ">= 1.2.0",
+				New:       ">= 1.3.0",
+			},
+			{
+				Component: "kubernetes",
+				Section:   "defaults",
+				Old:       ">= 1.16.0",
+			},
+		},
+		AddedExceptions: []ExceptionReport{
+			{
+				Component: "cert-exporter",
+				Reason:    "customer upgrade pending",
+				Release:   "v11.0.0",
+				Section:   "baseline",
+				Version:   ">= 1.3.0",
+			},
+		},
+	}
+	if diff := cmp.Diff(Diff(beforeRequests, afterRequests), expected); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func Test_Requests_Summary(t *testing.T) {
+	data := `releases:
+- name: ">= 11.0.0"
+  requests:
+  - name: kubernetes
+    version: ">= 1.16.0"
+    except:
+    - releaseVersion: v11.0.1
+      reason: customer upgrade pending
+    - releaseVersion: v11.1.0
+      reason: blocked by calico
+  - name: calico
+    version: ">= 3.10.0"
+- name: ">= 12.0.0"
+  requests:
+  - name: coredns
+    version: ">= 1.6.0"
+`
+
+	var requests Requests
+	err := requests.Load([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "release patterns: 2\nrequests: 3\nexceptions: 2\n"
+	if diff := cmp.Diff(requests.Summary(), expected); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func Test_Requests_Exceptions(t *testing.T) {
+	data := `releases:
+- name: ">= 11.0.0"
+  requests:
+  - name: kubernetes
+    version: ">= 1.16.0"
+    issue: https://github.com/giantswarm/roadmap/issues/1
+    except:
+    - releaseVersion: v11.0.1
+      reason: customer upgrade pending
+    - releaseVersion: v11.1.0
+      reason: blocked by calico
+  - name: calico
+    version: ">= 3.10.0"
+- name: ">= 12.0.0"
+  requests:
+  - name: coredns
+    version: ">= 1.6.0"
+    except:
+    - releaseVersion: v12.0.0
+      reason: regression in 1.6.0
+`
+
+	var requests Requests
+	err := requests.Load([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []ExceptionReport{
+		{
+			Component: "kubernetes",
+			Issue:     "https://github.com/giantswarm/roadmap/issues/1",
+			Pattern:   ">= 11.0.0",
+			Reason:    "customer upgrade pending",
+			Release:   "v11.0.1",
+			Version:   ">= 1.16.0",
+		},
+		{
+			Component: "kubernetes",
+			Issue:     "https://github.com/giantswarm/roadmap/issues/1",
+			Pattern:   ">= 11.0.0",
+			Reason:    "blocked by calico",
+			Release:   "v11.1.0",
+			Version:   ">= 1.16.0",
+		},
+		{
+			Component: "coredns",
+			Pattern:   ">= 12.0.0",
+			Reason:    "regression in 1.6.0",
+			Release:   "v12.0.0",
+			Version:   ">= 1.6.0",
+		},
+	}
+	if diff := cmp.Diff(requests.Exceptions(), expected); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func Test_Requests_Canonicalize(t *testing.T) {
+	data := `releases:
+- name: ">= 11.0.0"
+  requests:
+  - name: kubernetes
+    version: ">=1.16.0"
+  - name: calico
+    version: ">= 3.10.0, < 4.0.0"
+- name: ">=12.0.0"
+  requests:
+  - name: coredns
+    version: ">=1.6.0 || >=2.0.0"
+`
+
+	var requests Requests
+	err := requests.Load([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nonCanonical, err := requests.NonCanonicalConstraints()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []NonCanonicalConstraint{
+		{
+			Constraint: ">= 11.0.0",
+			Canonical:  ">=11.0.0",
+			Pattern:    ">= 11.0.0",
+		},
+		{
+			Component:  "calico",
+			Constraint: ">= 3.10.0, < 4.0.0",
+			Canonical:  ">=3.10.0 <4.0.0",
+			Pattern:    ">= 11.0.0",
+		},
+	}
+	if diff := cmp.Diff(nonCanonical, expected); diff != "" {
+		t.Error(diff)
+	}
+
+	err = requests.Canonicalize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonCanonical, err = requests.NonCanonicalConstraints()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nonCanonical) > 0 {
+		t.Errorf("expected no non-canonical constraints after Canonicalize, got %#v", nonCanonical)
+	}
+	if requests.requests[0].Name != ">=11.0.0" || requests.requests[0].Requests[1].Version != ">=3.10.0 <4.0.0" {
+		t.Errorf("unexpected canonical requests %#v", requests.requests)
+	}
+}
+
+func Test_Requests_BaselineAndDefaults(t *testing.T) {
+	data := `baseline:
+- name: cert-exporter
+  version: ">= 1.2.0"
+  except:
+  - releaseVersion: v11.0.0
+    reason: customer upgrade pending
+defaults:
+- name: kubernetes
+  version: ">=1.16.0"
+releases: []
+`
+
+	var requests Requests
+	err := requests.Load([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedSummary := "release patterns: 0\nrequests: 2\nexceptions: 1\n"
+	if diff := cmp.Diff(requests.Summary(), expectedSummary); diff != "" {
+		t.Error(diff)
+	}
+
+	expectedExceptions := []ExceptionReport{
+		{
+			Component: "cert-exporter",
+			Reason:    "customer upgrade pending",
+			Release:   "v11.0.0",
+			Section:   "baseline",
+			Version:   ">= 1.2.0",
+		},
+	}
+	if diff := cmp.Diff(requests.Exceptions(), expectedExceptions); diff != "" {
+		t.Error(diff)
+	}
+
+	nonCanonical, err := requests.NonCanonicalConstraints()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedNonCanonical := []NonCanonicalConstraint{
+		{
+			Component:  "cert-exporter",
+			Constraint: ">= 1.2.0",
+			Canonical:  ">=1.2.0",
+			Section:    "baseline",
+		},
+	}
+	if diff := cmp.Diff(nonCanonical, expectedNonCanonical); diff != "" {
+		t.Error(diff)
+	}
+
+	err = requests.Canonicalize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requests.baseline[0].Version != ">=1.2.0" {
+		t.Errorf("expected canonical baseline version, got %q", requests.baseline[0].Version)
+	}
+}
+
+func Test_Requests_Sort(t *testing.T) {
+	data := `releases:
+- name: ">=12.0.0"
+  requests:
+  - name: kubernetes
+    version: ">=1.17.0"
+- name: ">=9.0.0 <12.0.0"
+  requests:
+  - name: kubernetes
+    version: ">=1.16.0"
+- name: "<9.0.0"
+  requests:
+  - name: kubernetes
+    version: ">=1.15.0"
+- name: ">=11.0.0"
+  requests:
+  - name: calico
+    version: ">=3.10.0"
+`
+
+	var requests Requests
+	err := requests.Load([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unsorted, err := requests.UnsortedPatterns()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(unsorted, []string{">=9.0.0 <12.0.0", "<9.0.0"}); diff != "" {
+		t.Error(diff)
+	}
+
+	err = requests.Sort()
+	if err != nil {
+		t.Fatal(err)
+	}
+	unsorted, err = requests.UnsortedPatterns()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(unsorted) > 0 {
+		t.Errorf("expected no unsorted patterns after Sort, got %#v", unsorted)
+	}
+	if diff := cmp.Diff(requests.Patterns(), []string{"<9.0.0", ">=9.0.0 <12.0.0", ">=11.0.0", ">=12.0.0"}); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func Test_Requests_FilterByComponent(t *testing.T) {
+	data := `baseline:
+- name: chart-operator
+  version: ">=0.13.0"
+releases:
+- name: ">=11.0.0"
+  requests:
+  - name: kubernetes
+    version: ">=1.16.0"
+  - name: chart-operator
+    version: ">=1.0.0"
+    issue: https://github.com/giantswarm/roadmap/issues/1
+    except:
+    - releaseVersion: v11.0.1
+      reason: legacy
+- name: ">=12.0.0"
+  requests:
+  - name: chart-operator
+    version: ">=2.0.0"
+`
+
+	var requests Requests
+	err := requests.Load([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []VersionRequestWithPattern{
+		{
+			Component: "chart-operator",
+			Version:   ">=0.13.0",
+		},
+		{
+			Component:  "chart-operator",
+			Exceptions: []string{"v11.0.1"},
+			Issue:      "https://github.com/giantswarm/roadmap/issues/1",
+			Pattern:    ">=11.0.0",
+			Version:    ">=1.0.0",
+		},
+		{
+			Component: "chart-operator",
+			Pattern:   ">=12.0.0",
+			Version:   ">=2.0.0",
+		},
+	}
+	if diff := cmp.Diff(requests.FilterByComponent("chart-operator"), expected); diff != "" {
+		t.Error(diff)
+	}
+	if filtered := requests.FilterByComponent("calico"); len(filtered) > 0 {
+		t.Errorf("expected no requests for calico, got %#v", filtered)
+	}
+}
+
+func Test_Requests_RemoveException(t *testing.T) {
+	data := `baseline:
+- name: kubernetes
+  version: ">= 1.15.0"
+  except:
+  - releaseVersion: v11.0.0
+    reason: legacy release
+releases:
+- name: ">= 11.0.0"
+  requests:
+  - name: kubernetes
+    version: ">= 1.16.0"
+    except:
+    - releaseVersion: v11.0.1
+      reason: customer upgrade pending
+    - releaseVersion: v11.1.0
+      reason: blocked by calico
+`
+
+	testCases := []struct {
+		name           string
+		pattern        string
+		component      string
+		releaseVersion string
+		expectedResult bool
+		expected       []ExceptionReport
+	}{
+		{
+			name:           "case 0: remove existing exception",
+			pattern:        ">= 11.0.0",
+			component:      "kubernetes",
+			releaseVersion: "v11.0.1",
+			expectedResult: true,
+			expected: []ExceptionReport{
+				{
+					Component: "kubernetes",
+					Reason:    "legacy release",
+					Release:   "v11.0.0",
+					Section:   "baseline",
+					Version:   ">= 1.15.0",
+				},
+				{
+					Component: "kubernetes",
+					Pattern:   ">= 11.0.0",
+					Reason:    "blocked by calico",
+					Release:   "v11.1.0",
+					Version:   ">= 1.16.0",
+				},
+			},
+		},
+		{
+			name:           "case 1: no exception for the release",
+			pattern:        ">= 11.0.0",
+			component:      "kubernetes",
+			releaseVersion: "v11.2.0",
+			expectedResult: false,
+		},
+		{
+			name:           "case 2: no request for the component",
+			pattern:        ">= 11.0.0",
+			component:      "calico",
+			releaseVersion: "v11.0.1",
+			expectedResult: false,
+		},
+		{
+			name:           "case 3: remove baseline exception",
+			pattern:        "",
+			component:      "kubernetes",
+			releaseVersion: "v11.0.0",
+			expectedResult: true,
+			expected: []ExceptionReport{
+				{
+					Component: "kubernetes",
+					Pattern:   ">= 11.0.0",
+					Reason:    "customer upgrade pending",
+					Release:   "v11.0.1",
+					Version:   ">= 1.16.0",
+				},
+				{
+					Component: "kubernetes",
+					Pattern:   ">= 11.0.0",
+					Reason:    "blocked by calico",
+					Release:   "v11.1.0",
+					Version:   ">= 1.16.0",
+				},
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			var requests Requests
+			err := requests.Load([]byte(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			before := requests.Exceptions()
+
+			removed := requests.RemoveException(tc.pattern, tc.component, tc.releaseVersion)
+			if removed != tc.expectedResult {
+				t.Fatalf("expected %t, got %t", tc.expectedResult, removed)
+			}
+
+			expected := tc.expected
+			if !tc.expectedResult {
+				expected = before
+			}
+			if diff := cmp.Diff(requests.Exceptions(), expected); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func Test_Requests_constraintMinimum(t *testing.T) {
+	testCases := []struct {
+		name       string
+		constraint string
+		expected   string
+	}{
+		{
+			name:       "case 0: greater or equal",
+			constraint: ">= 1.5.0",
+			expected:   "1.5.0",
+		},
+		{
+			name:       "case 1: greater than",
+			constraint: ">1.5.0",
+			expected:   "1.5.1",
+		},
+		{
+			name:       "case 2: range",
+			constraint: ">=1.5.0, <2.0.0",
+			expected:   "1.5.0",
+		},
+		{
+			name:       "case 3: lowest minimum across OR conditions",
+			constraint: ">=2.1.0 || ~1.4.2",
+			expected:   "1.4.2",
+		},
+		{
+			name:       "case 4: wildcard",
+			constraint: "1.3.x",
+			expected:   "1.3.0",
+		},
+		{
+			name:       "case 5: upper bound only",
+			constraint: "<2.0.0",
+			expected:   "",
+		},
+		{
+			name:       "case 6: greater than partial minor version",
+			constraint: ">1.19",
+			expected:   "1.20.0",
+		},
+		{
+			name:       "case 7: greater than partial major version",
+			constraint: ">1",
+			expected:   "2.0.0",
+		},
+		{
+			name:       "case 8: hyphen range",
+			constraint: "1.18.0 - 1.20.0",
+			expected:   "1.18.0",
+		},
+		{
+			name:       "case 9: highest lower bound of AND condition",
+			constraint: ">=1.18.0 >1.18.2 <=1.20.0",
+			expected:   "1.18.3",
+		},
+		{
+			name:       "case 10: caret",
+			constraint: "^1.4.2",
+			expected:   "1.4.2",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			minimum, err := constraintMinimum(tc.constraint)
+			if err != nil {
+				t.Fatal(err)
+			}
+			actual := ""
+			if minimum != nil {
+				actual = minimum.String()
+			}
+			if actual != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func Test_Requests_constraintSatisfiable(t *testing.T) {
+	testCases := []struct {
+		name       string
+		constraint string
+		expected   bool
+	}{
+		{
+			name:       "case 0: greater than partial minor version",
+			constraint: ">1.19",
+			expected:   true,
+		},
+		{
+			name:       "case 1: greater than partial major version",
+			constraint: ">1",
+			expected:   true,
+		},
+		{
+			name:       "case 2: greater than short version",
+			constraint: ">1.2",
+			expected:   true,
+		},
+		{
+			name:       "case 3: exclusions above the lower bound",
+			constraint: ">=1.2.0 !=1.2.0 !=1.2.1",
+			expected:   true,
+		},
+		{
+			name:       "case 4: pre-release lower bound",
+			constraint: ">=1.0.0-alpha <1.0.0",
+			expected:   true,
+		},
+		{
+			name:       "case 5: equal inclusive bounds",
+			constraint: ">=1.2.0 <=1.2.0",
+			expected:   true,
+		},
+		{
+			name:       "case 6: lower bound above upper bound",
+			constraint: ">=2.0.0 <1.0.0",
+			expected:   false,
+		},
+		{
+			name:       "case 7: exclusive bound equal to inclusive bound",
+			constraint: ">=1.2.0 <1.2.0",
+			expected:   false,
+		},
+		{
+			name:       "case 8: satisfiable OR condition",
+			constraint: ">=2.0.0 <1.0.0 || ~1.4.2",
+			expected:   true,
+		},
+		{
+			name:       "case 9: hyphen range",
+			constraint: "1.18.0 - 1.20.0",
+			expected:   true,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			satisfiable, err := constraintSatisfiable(tc.constraint)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if satisfiable != tc.expected {
+				t.Errorf("expected satisfiable == %t, got %t", tc.expected, satisfiable)
+			}
+		})
+	}
+}
+
+func Test_Requests_Check_Exceptions(t *testing.T) {
+	data := `releases:
+- name: ">= 11.0.0"
+  requests:
+  - name: kubernetes
+    version: ">= 1.16.0"
+    except:
+    - releaseVersion: v11.0.1
+      reason: customer upgrade pending
+    - releaseVersion: v11.1.0
+      reason: blocked by calico
+`
+
+	testCases := []struct {
+		name        string
+		release     string
+		expectError bool
+	}{
+		{
+			name:    "case 0: first excepted release",
+			release: "v11.0.1",
+		},
+		{
+			name:    "case 1: last excepted release",
+			release: "v11.1.0",
+		},
+		{
+			name:        "case 2: release without exception",
+			release:     "v11.0.0",
+			expectError: true,
+		},
+		{
+			name:        "case 3: later release without exception",
+			release:     "v11.2.0",
+			expectError: true,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			var requests Requests
+			err := requests.Load([]byte(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = requests.Check(testRelease(tc.release, map[string]string{"kubernetes": "1.15.0"}))
+			if tc.expectError && err == nil {
+				t.Fatal("error == nil, want non-nil")
+			}
+			if !tc.expectError && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		})
+	}
+}
+
+func Test_Requests_Check_Baseline(t *testing.T) {
+	data := `baseline:
+- name: kubernetes
+  version: ">= 1.16.0"
+  except:
+  - releaseVersion: 9.0.0
+    reason: legacy
+releases:
+- name: ">= 12.0.0"
+  requests:
+  - name: calico
+    version: ">= 3.10.0"
+`
+
+	testCases := []struct {
+		name          string
+		release       v1alpha1.Release
+		errorContains string
+	}{
+		{
+			name:          "case 0: release below the baseline without matching pattern",
+			release:       testRelease("v10.0.0", map[string]string{"kubernetes": "1.15.0"}),
+			errorContains: "requested: kubernetes: >= 1.16.0 \tactual: 1.15.0",
+		},
+		{
+			name:    "case 1: release meeting the baseline",
+			release: testRelease("v10.0.0", map[string]string{"kubernetes": "1.16.2"}),
+		},
+		{
+			name:    "case 2: release excepted from the baseline",
+			release: testRelease("v9.0.0", map[string]string{"kubernetes": "1.15.0"}),
+		},
+		{
+			name:          "case 3: baseline and pattern requests combined",
+			release:       testRelease("v12.0.0", map[string]string{"kubernetes": "1.16.2", "calico": "3.9.0"}),
+			errorContains: "requested: calico: >= 3.10.0 \tactual: 3.9.0",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			var requests Requests
+			err := requests.Load([]byte(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = requests.Validate()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = requests.Check(tc.release)
+			if tc.errorContains == "" && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if tc.errorContains != "" && (err == nil || !strings.Contains(err.Error(), tc.errorContains)) {
+				t.Fatalf("expected error containing %q, got %v", tc.errorContains, err)
+			}
+		})
+	}
+}
+
+func Test_Requests_Check_Defaults(t *testing.T) {
+	data := `defaults:
+- name: kubernetes
+  version: ">= 1.16.0"
+  except:
+  - releaseVersion: 9.0.0
+    reason: legacy
+releases:
+- name: ">= 12.0.0"
+  requests:
+  - name: calico
+    version: ">= 3.10.0"
+`
+
+	testCases := []struct {
+		name          string
+		release       v1alpha1.Release
+		errorContains string
+	}{
+		{
+			name:          "case 0: release matching no pattern checked against defaults",
+			release:       testRelease("v10.0.0", map[string]string{"kubernetes": "1.15.0"}),
+			errorContains: "requested: kubernetes: >= 1.16.0 \tactual: 1.15.0",
+		},
+		{
+			name:    "case 1: release matching no pattern meeting defaults",
+			release: testRelease("v10.0.0", map[string]string{"kubernetes": "1.16.2"}),
+		},
+		{
+			name:    "case 2: release excepted from defaults",
+			release: testRelease("v9.0.0", map[string]string{"kubernetes": "1.15.0"}),
+		},
+		{
+			name:    "case 3: defaults don't apply to releases matching a pattern",
+			release: testRelease("v12.0.0", map[string]string{"kubernetes": "1.15.0", "calico": "3.10.0"}),
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			var requests Requests
+			err := requests.Load([]byte(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = requests.Validate()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = requests.Check(tc.release)
+			if tc.errorContains == "" && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if tc.errorContains != "" && (err == nil || !strings.Contains(err.Error(), tc.errorContains)) {
+				t.Fatalf("expected error containing %q, got %v", tc.errorContains, err)
+			}
+		})
+	}
+}
+
+func Test_Requests_CheckVersions(t *testing.T) {
+	data := `releases:
+- name: ">= 11.0.0"
+  requests:
+  - name: kubernetes
+    version: ">= 1.17.0"
+    issue: https://github.com/giantswarm/roadmap/issues/1
+  - name: calico
+    version: ">= 3.10.0"
+`
+
+	var requests Requests
+	err := requests.Load([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	current := map[string]string{
+		"calico":     "3.10.1",
+		"kubernetes": "1.16.3",
+	}
+	unsatisfied, err := requests.CheckVersions("v11.1.0", current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []UnsatisfiedRequest{
+		{
+			Actual:     "1.16.3",
+			Component:  "kubernetes",
+			Constraint: ">= 1.17.0",
+			Issue:      "https://github.com/giantswarm/roadmap/issues/1",
+		},
+	}
+	if diff := cmp.Diff(unsatisfied, expected); diff != "" {
+		t.Fatal(diff)
+	}
+
+	// Simulate upgrading kubernetes for the next release.
+	proposed := map[string]string{
+		"calico":     "3.10.1",
+		"kubernetes": "1.17.2",
+	}
+	unsatisfied, err = requests.CheckVersions("v11.2.0", proposed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(unsatisfied) != 0 {
+		t.Fatalf("expected proposed upgrade to satisfy all requests, got %#v", unsatisfied)
+	}
+}
+
+func Test_Requests_IsSatisfied(t *testing.T) {
+	data := `releases:
+- name: ">= 11.0.0"
+  requests:
+  - name: kubernetes
+    version: ">= 1.17.0"
+`
+
+	var requests Requests
+	err := requests.Load([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name     string
+		state    v1alpha1.ReleaseState
+		version  string
+		expected bool
+	}{
+		{
+			name:     "case 0: satisfied release",
+			state:    v1alpha1.StateActive,
+			version:  "1.17.2",
+			expected: true,
+		},
+		{
+			name:     "case 1: unsatisfied release",
+			state:    v1alpha1.StateActive,
+			version:  "1.16.3",
+			expected: false,
+		},
+		{
+			name:     "case 2: WIP release isn't checked",
+			state:    v1alpha1.StateWIP,
+			version:  "1.16.3",
+			expected: true,
+		},
+		{
+			name:     "case 3: deprecated release isn't checked",
+			state:    v1alpha1.StateDeprecated,
+			version:  "1.16.3",
+			expected: true,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			release := v1alpha1.Release{}
+			release.Name = "v11.1.0"
+			release.Spec.State = tc.state
+			release.Spec.Components = []v1alpha1.ReleaseSpecComponent{{Name: "kubernetes", Version: tc.version}}
+
+			satisfied, err := requests.IsSatisfied(release)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if satisfied != tc.expected {
+				t.Fatalf("satisfied == %t, want %t", satisfied, tc.expected)
+			}
+			if checked := requests.Check(release) == nil; satisfied != checked {
+				t.Fatalf("satisfied == %t, but Check passing == %t", satisfied, checked)
+			}
+		})
+	}
+}
+
+func Test_Requests_Check_Concurrent(t *testing.T) {
+	data := `baseline:
+- name: calico
+  version: ">= 3.10.0"
+releases:
+- name: ">= 11.0.0"
+  requests:
+  - name: kubernetes
+    version: ">= 1.16.0"
+    except:
+    - releaseVersion: v11.0.1
+      reason: customer upgrade pending
+`
+
+	var requests Requests
+	err := requests.Load([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	releases := []v1alpha1.Release{
+		testRelease("v11.0.0", map[string]string{"calico": "3.10.0", "kubernetes": "1.16.0"}),
+		testRelease("v11.0.1", map[string]string{"calico": "3.10.0", "kubernetes": "1.15.0"}),
+		testRelease("v11.1.0", map[string]string{"calico": "3.9.0", "kubernetes": "1.16.0"}),
+	}
+	expectError := []bool{false, false, true}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 8*len(releases))
+	for i := 0; i < 8; i++ {
+		for j := range releases {
+			wg.Add(1)
+			go func(j int) {
+				defer wg.Done()
+				err := requests.Check(releases[j])
+				if (err != nil) != expectError[j] {
+					errs <- fmt.Errorf("release %s: unexpected result %v", releases[j].Name, err)
+				}
+			}(j)
+		}
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func Test_Requests_CheckAll(t *testing.T) {
+	data := `releases:
+- name: ">= 11.0.0"
+  requests:
+  - name: kubernetes
+    version: ">= 1.16.0"
+`
+
+	var requests Requests
+	err := requests.Load([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	releases := []v1alpha1.Release{
+		testRelease("v11.0.0", map[string]string{"kubernetes": "1.15.0"}),
+		testRelease("v11.1.0", map[string]string{"kubernetes": "1.16.3"}),
+		testRelease("v11.2.0", map[string]string{"kubernetes": "1.15.5"}),
+	}
+
+	err = requests.CheckAll(releases)
+	if err == nil {
+		t.Fatal("error == nil, want non-nil")
+	}
+	if !strings.Contains(err.Error(), "Release v11.0.0") || strings.Contains(err.Error(), "v11.2.0") {
+		t.Errorf("expected fail-fast error for v11.0.0 only, got %s", err)
+	}
+
+	err = requests.CheckAll(releases, WithCollectAll())
+	if err == nil {
+		t.Fatal("error == nil, want non-nil")
+	}
+	if !strings.Contains(err.Error(), "2 releases do not meet") || !strings.Contains(err.Error(), "Release v11.0.0") || !strings.Contains(err.Error(), "Release v11.2.0") {
+		t.Errorf("expected collected errors for v11.0.0 and v11.2.0, got %s", err)
+	}
+
+	err = requests.CheckAll(releases[1:2], WithCollectAll())
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
+
+func Test_Requests_Check_Miss(t *testing.T) {
+	data := `releases:
+- name: ">= 11.0.0"
+  requests:
+  - name: kubernetes
+    version: ">= 1.5.2"
+`
+
+	testCases := []struct {
+		name          string
+		version       string
+		errorContains string
+	}{
+		{
+			name:          "case 0: close miss",
+			version:       "1.5.1",
+			errorContains: "requested: kubernetes: >= 1.5.2 \tactual: 1.5.1 (close miss: 1 patch behind 1.5.2)",
+		},
+		{
+			name:          "case 1: minor miss",
+			version:       "1.4.9",
+			errorContains: "actual: 1.4.9 (1 minor behind 1.5.2)",
+		},
+		{
+			name:          "case 2: major miss",
+			version:       "0.9.0",
+			errorContains: "actual: 0.9.0 (1 major behind 1.5.2)",
+		},
+		{
+			name:    "case 3: satisfied",
+			version: "1.5.2",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			var requests Requests
+			err := requests.Load([]byte(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = requests.Check(testRelease("v11.0.0", map[string]string{"kubernetes": tc.version}))
+			if tc.errorContains == "" && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if tc.errorContains != "" && (err == nil || !strings.Contains(err.Error(), tc.errorContains)) {
+				t.Fatalf("expected error containing %q, got %v", tc.errorContains, err)
+			}
+		})
+	}
+}
+
+// testRelease returns an active release with the given name and component versions.
+func testRelease(name string, components map[string]string) v1alpha1.Release {
+	release := v1alpha1.Release{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: v1alpha1.ReleaseSpec{
+			State: v1alpha1.StateActive,
+		},
+	}
+	for componentName, version := range components {
+		release.Spec.Components = append(release.Spec.Components, v1alpha1.ReleaseSpecComponent{
+			Name:    componentName,
+			Version: version,
+		})
+	}
+	return release
+}
+
+func Test_Requests_SuggestFixes(t *testing.T) {
+	data := `releases:
+- name: ">= 11.0.0"
+  requests:
+  - name: chart-operator
+    version: ">=1.3.0"
+  - name: kubernetes
+    version: ">= 1.16.0"
+  - name: coredns
+    version: "~1.6.5"
+`
+
+	var requests Requests
+	err := requests.Load([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	release := testRelease("v11.0.0", map[string]string{
+		"chart-operator": "1.2.0",
+		"kubernetes":     "1.16.3",
+	})
+	fixes, err := requests.SuggestFixes(release)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Fix{
+		{
+			Component:  "chart-operator",
+			Constraint: ">=1.3.0",
+			Current:    "1.2.0",
+			Minimum:    "1.3.0",
+		},
+		{
+			Component:  "coredns",
+			Constraint: "~1.6.5",
+			Minimum:    "1.6.5",
+		},
+	}
+	if diff := cmp.Diff(fixes, expected); diff != "" {
+		t.Fatal(diff)
+	}
+	if fixes[0].String() != "bump chart-operator from 1.2.0 to >=1.3.0" {
+		t.Errorf("unexpected fix description %q", fixes[0].String())
+	}
+}
+
+func Test_Requests_SuggestFixes_Bounds(t *testing.T) {
+	testCases := []struct {
+		name         string
+		constraint   string
+		expectedFix  string
+		expectedMiss string
+	}{
+		{
+			name:         "case 0: greater than partial version",
+			constraint:   ">1.19",
+			expectedFix:  "bump kubernetes from 1.17.0 to >=1.20.0",
+			expectedMiss: "3 minor behind 1.20.0",
+		},
+		{
+			name:         "case 1: hyphen range",
+			constraint:   "1.18.0 - 1.20.0",
+			expectedFix:  "bump kubernetes from 1.17.0 to >=1.18.0",
+			expectedMiss: "1 minor behind 1.18.0",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			data := fmt.Sprintf("releases:\n- name: \">= 11.0.0\"\n  requests:\n  - name: kubernetes\n    version: %q\n", tc.constraint)
+			var requests Requests
+			err := requests.Load([]byte(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			fixes, err := requests.SuggestFixes(testRelease("v11.0.0", map[string]string{"kubernetes": "1.17.0"}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(fixes) != 1 || fixes[0].String() != tc.expectedFix {
+				t.Errorf("expected fix %q, got %v", tc.expectedFix, fixes)
+			}
+			if miss := describeMiss("1.17.0", tc.constraint); miss != tc.expectedMiss {
+				t.Errorf("expected miss %q, got %q", tc.expectedMiss, miss)
+			}
+		})
+	}
+}
+
+func Test_Requests_MissingComponents(t *testing.T) {
+	data := `baseline:
+- name: cert-exporter
+  version: ">= 1.2.0"
+releases:
+- name: ">= 11.0.0"
+  requests:
+  - name: kubernetes
+    version: ">= 1.16.0"
+  - name: coredns
+    version: "~1.6.5"
+  - name: calico
+    version: ">= 3.10.0"
+- name: ">= 12.0.0"
+  requests:
+  - name: calico
+    version: ">= 3.12.0"
+`
+
+	var requests Requests
+	err := requests.Load([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	release := testRelease("v12.0.0", map[string]string{
+		"kubernetes": "1.15.0",
+	})
+	release.Spec.Apps = []v1alpha1.ReleaseSpecApp{
+		{Name: "cert-exporter", Version: "1.1.0"},
+	}
+	missing, err := requests.MissingComponents(release)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// kubernetes and cert-exporter are shipped, even though at versions not meeting the requests.
+	expected := []string{"calico", "coredns"}
+	if diff := cmp.Diff(missing, expected); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func Test_Requests_UnusedExceptions(t *testing.T) {
+	data := `baseline:
+- name: cert-exporter
+  version: ">= 1.2.0"
+  except:
+  - releaseVersion: v11.0.0
+    reason: legacy exporter
+releases:
+- name: ">= 11.0.0"
+  requests:
+  - name: kubernetes
+    version: ">= 1.16.0"
+    issue: https://github.com/giantswarm/roadmap/issues/1
+    except:
+    - releaseVersion: v11.0.0
+      reason: upgraded later
+    - releaseVersion: v11.1.0
+      reason: still on 1.15
+    - releaseVersion: v11.9.0
+      reason: release not given
+`
+
+	var requests Requests
+	err := requests.Load([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	releases := []v1alpha1.Release{
+		testRelease("v11.0.0", map[string]string{"kubernetes": "1.16.3"}),
+		testRelease("v11.1.0", map[string]string{"kubernetes": "1.15.5"}),
+	}
+	releases[0].Spec.Apps = []v1alpha1.ReleaseSpecApp{
+		{Name: "cert-exporter", Version: "1.1.0"},
+	}
+
+	unused, err := requests.UnusedExceptions(releases)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []ExceptionReport{
+		{
+			Component: "kubernetes",
+			Issue:     "https://github.com/giantswarm/roadmap/issues/1",
+			Pattern:   ">= 11.0.0",
+			Reason:    "upgraded later",
+			Release:   "v11.0.0",
+			Version:   ">= 1.16.0",
+		},
+	}
+	if diff := cmp.Diff(unused, expected); diff != "" {
+		t.Error(diff)
+	}
+}
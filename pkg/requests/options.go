@@ -0,0 +1,43 @@
+package requests
+
+// CheckOption configures optional behaviour of Requests.CheckAll.
+type CheckOption func(o *checkOptions)
+
+type checkOptions struct {
+	collectAll bool
+}
+
+// WithCollectAll makes CheckAll check every release instead of returning at the first one
+// which violates its requests.
+func WithCollectAll() CheckOption {
+	return func(o *checkOptions) {
+		o.collectAll = true
+	}
+}
+
+// ValidateOption configures optional checks of Requests.Validate.
+type ValidateOption func(o *validateOptions)
+
+type validateOptions struct {
+	disallowedOperators []string
+	issueReferences     bool
+}
+
+// WithDisallowedOperators makes Validate reject requested versions using any of the given
+// constraint operators, e.g. "=" to enforce ranges instead of exact pins. Terms without an
+// operator like "1.2.0" count as "=" unless they are partial or wildcard versions like "1.2"
+// or "1.2.x". The aliases "=>", "=<" and "~>" count as ">=", "<=" and "~".
+func WithDisallowedOperators(operators ...string) ValidateOption {
+	return func(o *validateOptions) {
+		o.disallowedOperators = append(o.disallowedOperators, operators...)
+	}
+}
+
+// WithIssueReferences makes Validate reject issues which are neither a full https URL nor an
+// issue reference like "#123" or "giantswarm/roadmap#123", so that every issue stays linkable.
+// Requests without an issue are still accepted.
+func WithIssueReferences() ValidateOption {
+	return func(o *validateOptions) {
+		o.issueReferences = true
+	}
+}
@@ -0,0 +1,91 @@
+package requests
+
+// requestSchema describes a single requested component or app version, see versionRequest.
+const requestSchema = `{
+  "type": "object",
+  "additionalProperties": false,
+  "required": ["name", "version"],
+  "properties": {
+    "issue": {
+      "description": "Issue tracking the request.",
+      "type": "string"
+    },
+    "name": {
+      "description": "Name of the requested component or app.",
+      "type": "string",
+      "minLength": 1
+    },
+    "version": {
+      "description": "Semver constraint the component or app version must satisfy.",
+      "type": "string",
+      "minLength": 1
+    },
+    "except": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "additionalProperties": false,
+        "required": ["releaseVersion"],
+        "properties": {
+          "releaseVersion": {
+            "description": "Version of the release excepted from the request.",
+            "type": "string",
+            "minLength": 1
+          },
+          "reason": {
+            "description": "Why the release is excepted.",
+            "type": "string"
+          }
+        }
+      }
+    }
+  }
+}`
+
+// jsonSchema describes the format of requests files, see requestsFile.
+const jsonSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "title": "Release requests",
+  "description": "Component and app versions requested for releases matching a semver constraint.",
+  "type": "object",
+  "additionalProperties": false,
+  "required": ["releases"],
+  "properties": {
+    "baseline": {
+      "description": "Requests applying to all active releases regardless of their version.",
+      "type": "array",
+      "items": ` + requestSchema + `
+    },
+    "defaults": {
+      "description": "Requests applying to active releases which match none of the release patterns.",
+      "type": "array",
+      "items": ` + requestSchema + `
+    },
+    "releases": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "additionalProperties": false,
+        "required": ["name", "requests"],
+        "properties": {
+          "name": {
+            "description": "Semver constraint matching the releases the requests apply to, e.g. \">=12.0.0\".",
+            "type": "string",
+            "minLength": 1
+          },
+          "requests": {
+            "type": "array",
+            "items": ` + requestSchema + `
+          }
+        }
+      }
+    }
+  }
+}
+`
+
+// JSONSchema returns a JSON Schema describing requests files, e.g. for use with
+// yaml-language-server.
+func JSONSchema() []byte {
+	return []byte(jsonSchema)
+}
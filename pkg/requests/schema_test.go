@@ -0,0 +1,111 @@
+package requests
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	"sigs.k8s.io/yaml"
+)
+
+func Test_Requests_JSONSchema(t *testing.T) {
+	var props v1.JSONSchemaProps
+	err := json.Unmarshal(JSONSchema(), &props)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var internal apiextensions.JSONSchemaProps
+	err = v1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(&props, &internal, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	validator, _, err := validation.NewSchemaValidator(&apiextensions.CustomResourceValidation{OpenAPIV3Schema: &internal})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name     string
+		document string
+		valid    bool
+	}{
+		{
+			name: "case 0: valid document",
+			document: `releases:
+- name: ">= 11.0.0"
+  requests:
+  - name: kubernetes
+    version: ">= 1.16.0"
+    issue: https://github.com/giantswarm/roadmap/issues/1
+    except:
+    - releaseVersion: v11.0.1
+      reason: legacy
+`,
+			valid: true,
+		},
+		{
+			name: "case 1: valid document with baseline",
+			document: `baseline:
+- name: kubernetes
+  version: ">= 1.16.0"
+releases: []
+`,
+			valid: true,
+		},
+		{
+			name: "case 2: valid document with defaults",
+			document: `defaults:
+- name: kubernetes
+  version: ">= 1.16.0"
+releases: []
+`,
+			valid: true,
+		},
+		{
+			name: "case 3: unknown field",
+			document: `releases:
+- name: ">= 11.0.0"
+  requests:
+  - name: kubernetes
+    version: ">= 1.16.0"
+    exceptions:
+    - releaseVersion: v11.0.1
+`,
+			valid: false,
+		},
+		{
+			name: "case 4: missing version",
+			document: `releases:
+- name: ">= 11.0.0"
+  requests:
+  - name: kubernetes
+`,
+			valid: false,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			data, err := yaml.YAMLToJSON([]byte(tc.document))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var document interface{}
+			err = json.Unmarshal(data, &document)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result := validator.Validate(document)
+			if result.IsValid() != tc.valid {
+				t.Errorf("expected valid == %t, got errors %v", tc.valid, result.Errors)
+			}
+		})
+	}
+}
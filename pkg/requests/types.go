@@ -1,6 +1,10 @@
 package requests
 
-// requestException represents a single release exception to a request.
+import "fmt"
+
+// requestException represents a single release exception to a request. Version is the exact
+// release version the request doesn't apply to, so it must be one of the releases matched by
+// the release pattern the request is listed under.
 type requestException struct {
 	Version string `yaml:"releaseVersion" json:"releaseVersion"`
 	Reason  string `yaml:"reason"`
@@ -20,7 +24,118 @@ type releaseRequest struct {
 	Requests []versionRequest `yaml:"requests"`
 }
 
+// ExceptionReport describes a single exception together with the request and release pattern
+// it belongs to.
+type ExceptionReport struct {
+	// Component is the name of the requested component or app.
+	Component string
+	Issue     string
+	// Pattern is the release pattern the request applies to. It is empty for baseline and
+	// default requests.
+	Pattern string
+	Reason  string
+	// Release is the version of the excepted release.
+	Release string
+	// Section is "baseline" or "defaults" for requests outside of release patterns and empty
+	// otherwise.
+	Section string
+	// Version is the requested version constraint.
+	Version string
+}
+
+// RequestsDiff is the structured difference between two sets of requests, see Diff.
+type RequestsDiff struct {
+	AddedPatterns   []string
+	RemovedPatterns []string
+	// ChangedVersions lists every request whose version differs, including requests which
+	// were added or removed.
+	ChangedVersions   []VersionChange
+	AddedExceptions   []ExceptionReport
+	RemovedExceptions []ExceptionReport
+}
+
+// UnsatisfiedRequest is a request a release doesn't meet along with the version it ships.
+type UnsatisfiedRequest struct {
+	// Actual is the shipped version, empty if the component or app isn't shipped at all.
+	Actual string
+	// Component is the name of the requested component or app.
+	Component string
+	// Constraint is the requested version constraint.
+	Constraint string
+	Issue      string
+}
+
+// VersionChange is a requested version which differs between two sets of requests.
+type VersionChange struct {
+	// Component is the name of the requested component or app.
+	Component string
+	// Pattern is the release pattern the request applies to. It is empty for baseline and
+	// default requests.
+	Pattern string
+	// Section is "baseline" or "defaults" for requests outside of release patterns and empty
+	// otherwise.
+	Section string
+	// Old is the previously requested version, empty if the request was added.
+	Old string
+	// New is the newly requested version, empty if the request was removed.
+	New string
+}
+
+// Fix suggests how to satisfy a request a release doesn't meet.
+type Fix struct {
+	// Component is the name of the requested component or app.
+	Component string
+	// Constraint is the requested version constraint.
+	Constraint string
+	// Current is the version shipped in the release, empty if it isn't shipped at all.
+	Current string
+	// Minimum is the lowest version satisfying the constraint, empty if the constraint has no
+	// lower bound.
+	Minimum string
+}
+
+func (f Fix) String() string {
+	if f.Current == "" {
+		return fmt.Sprintf("add %s at %s", f.Component, f.Constraint)
+	}
+	if f.Minimum == "" {
+		return fmt.Sprintf("change %s from %s to match %s", f.Component, f.Current, f.Constraint)
+	}
+	return fmt.Sprintf("bump %s from %s to >=%s", f.Component, f.Current, f.Minimum)
+}
+
+// NonCanonicalConstraint is a version constraint which isn't written in canonical form.
+type NonCanonicalConstraint struct {
+	// Component is the name of the requested component or app. It is empty when the
+	// constraint is the release pattern itself.
+	Component  string
+	Constraint string
+	Canonical  string
+	// Pattern is the release pattern the constraint belongs to. It is empty for baseline and
+	// default requests.
+	Pattern string
+	// Section is "baseline" or "defaults" for requests outside of release patterns and empty
+	// otherwise.
+	Section string
+}
+
+// VersionRequestWithPattern is a single request together with the release pattern it is
+// listed under, see FilterByComponent.
+type VersionRequestWithPattern struct {
+	// Component is the name of the requested component or app.
+	Component string
+	// Exceptions are the versions of the excepted releases.
+	Exceptions []string
+	Issue      string
+	// Pattern is the release pattern the request applies to. It is empty for baseline and
+	// default requests.
+	Pattern string
+	// Version is the requested version constraint.
+	Version string
+}
+
 type requestsFile struct {
+	Baseline []versionRequest `yaml:"baseline,omitempty" json:"baseline,omitempty"`
+	Defaults []versionRequest `yaml:"defaults,omitempty" json:"defaults,omitempty"`
 	Releases []releaseRequest `yaml:"releases"`
 }
-
@@ -0,0 +1,24 @@
+package validation
+
+import (
+	"embed"
+	"io/fs"
+	"testing"
+
+	"github.com/giantswarm/releaseclient/pkg/filesystem"
+)
+
+//go:embed testdata/embedded
+var embeddedProvider embed.FS
+
+func Test_Validation_Validate_Embedded(t *testing.T) {
+	root, err := fs.Sub(embeddedProvider, "testdata/embedded")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = Validate(filesystem.NewIOFS(root), "aws")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,21 @@
+package validation
+
+import "github.com/giantswarm/microerror"
+
+var providerNotFoundError = &microerror.Error{
+	Kind: "providerNotFoundError",
+}
+
+// IsProviderNotFound asserts providerNotFoundError.
+func IsProviderNotFound(err error) bool {
+	return microerror.Cause(err) == providerNotFoundError
+}
+
+var invalidIgnoresError = &microerror.Error{
+	Kind: "invalidIgnoresError",
+}
+
+// IsInvalidIgnores asserts invalidIgnoresError.
+func IsInvalidIgnores(err error) bool {
+	return microerror.Cause(err) == invalidIgnoresError
+}
@@ -2,9 +2,15 @@ package validation
 
 import (
 	"fmt"
+	"path"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/Masterminds/semver/v3"
 	"github.com/giantswarm/apiextensions/v2/pkg/apis/release/v1alpha1"
 	"github.com/giantswarm/microerror"
 	"github.com/giantswarm/versionbundle"
@@ -15,6 +21,7 @@ import (
 
 	"github.com/giantswarm/releaseclient/pkg/filesystem"
 	"github.com/giantswarm/releaseclient/pkg/key"
+	"github.com/giantswarm/releaseclient/pkg/patch"
 	requests2 "github.com/giantswarm/releaseclient/pkg/requests"
 )
 
@@ -41,46 +48,240 @@ func releasesToIndex(releases []v1alpha1.Release) []versionbundle.IndexRelease {
 			authorities = append(authorities, indexAuthority)
 		}
 		indexRelease := versionbundle.IndexRelease{
-			Active:      release.Spec.State == "active",
+			Active:      release.Spec.State == v1alpha1.StateActive,
 			Apps:        apps,
 			Authorities: authorities,
 			Date:        release.Spec.Date.Time,
-			Version:     release.Name,
+			Version:     key.NormalizeVersion(release.Name),
 		}
 		indexReleases = append(indexReleases, indexRelease)
 	}
 	return indexReleases
 }
 
-func validateRequests(fs filesystem.Filesystem, provider string) error {
+func loadRequests(r *run) (requests2.Requests, error) {
 	requests := requests2.Requests{}
 
-	{
-		requestsData, err := fs.ReadFile(filepath.Join(provider, key.RequestsFilename))
+	requestsData, err := r.readFile(filepath.Join(r.provider, key.RequestsFilename))
+	if err != nil {
+		return requests2.Requests{}, microerror.Mask(err)
+	}
+
+	err = requests.Load(requestsData)
+	if err != nil {
+		return requests2.Requests{}, microerror.Mask(err)
+	}
+
+	return requests, nil
+}
+
+// validateProviderName checks that the provider directory is named after a known provider so
+// that typos don't silently create an unused provider.
+func validateProviderName(r *run) error {
+	for _, provider := range r.options.knownProviders {
+		if provider == r.provider {
+			return nil
+		}
+	}
+
+	return microerror.Mask(fmt.Errorf("provider directory %s is not a known provider, known are %s", r.provider, strings.Join(r.options.knownProviders, ", ")))
+}
+
+func validateReleaseDirectories(r *run) error {
+	paths, err := releaseParentDirectories(r)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	for _, path := range paths {
+		directories, err := r.listDirectories(path)
 		if err != nil {
 			return microerror.Mask(err)
 		}
 
-		err = requests.Load(requestsData)
+		// Check that every directory named like a release contains a release manifest.
+		for _, directory := range directories {
+			_, err := parseVersion(directory)
+			if err != nil {
+				continue
+			}
+			exists, err := r.exists(filepath.Join(path, directory, key.ReleaseFilename))
+			if err != nil {
+				return microerror.Mask(err)
+			}
+			if !exists {
+				return microerror.Mask(fmt.Errorf("release directory %s/%s is missing %s", path, directory, key.ReleaseFilename))
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateReleaseDirectoryCase checks that no two release directories differ only by case, as
+// they collide on case-insensitive filesystems.
+func validateReleaseDirectoryCase(r *run) error {
+	paths, err := releaseParentDirectories(r)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	for _, path := range paths {
+		directories, err := r.listDirectories(path)
 		if err != nil {
 			return microerror.Mask(err)
 		}
+
+		seen := map[string]string{}
+		for _, directory := range directories {
+			folded := strings.ToLower(directory)
+			if other, ok := seen[folded]; ok {
+				return microerror.Mask(fmt.Errorf("release directories %s/%s and %s/%s differ only by case", path, other, path, directory))
+			}
+			seen[folded] = directory
+		}
+	}
+
+	return nil
+}
+
+// releaseParentDirectories returns the provider directory and, if it exists, its archived
+// directory.
+func releaseParentDirectories(r *run) ([]string, error) {
+	paths := []string{r.provider}
+
+	archivedPath := filepath.Join(r.provider, key.ArchivedDirectory)
+	exists, err := r.exists(archivedPath)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	if exists {
+		paths = append(paths, archivedPath)
 	}
 
-	releases, err := fs.FindReleases(provider, false)
+	return paths, nil
+}
+
+func validateRequests(r *run) error {
+	requests, err := loadRequests(r)
 	if err != nil {
 		return microerror.Mask(err)
 	}
 
-	for _, release := range releases {
-		err = requests.Check(release)
+	err = requests.Validate()
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	releases, err := r.findReleases(false)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	err = requests.CheckAll(releases)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+// validateRetiredProviderRequests checks that providers which aren't allowed anymore don't
+// request versions for their releases.
+func validateRetiredProviderRequests(r *run) error {
+	if len(r.options.allowedProviders) == 0 {
+		return nil
+	}
+	for _, provider := range r.options.allowedProviders {
+		if provider == r.provider {
+			return nil
+		}
+	}
+
+	requests, err := loadRequests(r)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	patterns := requests.Patterns()
+	if len(patterns) > 0 {
+		return microerror.Mask(fmt.Errorf("%s of retired provider %s requests versions for releases %s", key.RequestsFilename, r.provider, strings.Join(patterns, ", ")))
+	}
+
+	return nil
+}
+
+// validateRequestsCanonical warns about version constraints in the requests file which aren't
+// written in canonical form.
+func validateRequestsCanonical(r *run) error {
+	requests, err := loadRequests(r)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	nonCanonical, err := requests.NonCanonicalConstraints()
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	for _, c := range nonCanonical {
+		if c.Component == "" {
+			r.warnf("", "release pattern %q in %s/%s should be written as %q", c.Constraint, r.provider, key.RequestsFilename, c.Canonical)
+		} else if c.Section != "" {
+			r.warnf("", "version %q requested for %s in %s in %s/%s should be written as %q", c.Constraint, c.Component, c.Section, r.provider, key.RequestsFilename, c.Canonical)
+		} else {
+			r.warnf("", "version %q requested for %s in releases %q in %s/%s should be written as %q", c.Constraint, c.Component, c.Pattern, r.provider, key.RequestsFilename, c.Canonical)
+		}
+	}
+
+	return nil
+}
+
+func validateRequestsOrder(r *run) error {
+	requests, err := loadRequests(r)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	unsorted, err := requests.UnsortedPatterns()
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	for _, pattern := range unsorted {
+		r.warnf("", "release pattern %q in %s/%s is out of order, patterns should be sorted by the lowest release they match", pattern, r.provider, key.RequestsFilename)
+	}
+
+	return nil
+}
+
+// validateRedundantExceptions warns about request exceptions whose excepted release already
+// satisfies the request, so the exception can be removed.
+func validateRedundantExceptions(r *run) error {
+	requests, err := loadRequests(r)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	releases, err := r.findReleases(false)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	unused, err := requests.UnusedExceptions(releases)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	for _, exception := range unused {
+		r.warnf(exception.Release, "exception for %s release %s from request %s %s in %s/%s is redundant, the release already satisfies it", r.provider, exception.Release, exception.Component, exception.Version, r.provider, key.RequestsFilename)
 	}
 
 	return nil
 }
 
-func validateReleaseNotes(fs filesystem.Filesystem, provider string) error {
-	releases, err := fs.FindReleases(provider, false)
+func validateReleaseNotes(r *run) error {
+	releases, err := r.findReleases(false)
 	if err != nil {
 		return microerror.Mask(err)
 	}
@@ -88,67 +289,283 @@ func validateReleaseNotes(fs filesystem.Filesystem, provider string) error {
 	for _, release := range releases {
 		// Check that the version in the first line of the release notes is correct.
 		{
-			releaseNotesData, err := fs.ReadFile(filepath.Join(provider, release.Name, key.ReadmeFilename))
+			releaseNotesData, err := r.readFile(filepath.Join(r.provider, release.Name, key.ReadmeFilename))
 			if err != nil {
-				return microerror.Mask(fmt.Errorf("missing file for %s release %s: %s", provider, release.Name, err))
+				return microerror.Mask(fmt.Errorf("missing file for %s release %s: %s", r.provider, release.Name, err))
 			}
 			releaseNotesLines := strings.Split(string(releaseNotesData), "\n")
-			if len(releaseNotesLines) == 0 || !strings.Contains(releaseNotesLines[0], strings.TrimPrefix(release.Name, "v")) {
-				return microerror.Mask(fmt.Errorf("expected release notes for %s release %s to contain the release version on the first line", provider, release.Name))
+			token := versionTokenPattern.FindString(releaseNotesLines[0])
+			if token == "" {
+				return microerror.Mask(fmt.Errorf("expected release notes for %s release %s to contain the release version on the first line", r.provider, release.Name))
+			}
+			matches, err := versionsEqual(token, release.Name)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+			if !matches {
+				return microerror.Mask(fmt.Errorf("expected release notes for %s release %s to contain the release version on the first line, found %s", r.provider, release.Name, token))
+			}
+
+			prefixed := strings.HasPrefix(token, "v")
+			if r.options.versionPrefix == VersionPrefixRequired && !prefixed {
+				return microerror.Mask(fmt.Errorf("expected release notes for %s release %s to write version %s with a v prefix on the first line", r.provider, release.Name, token))
+			}
+			if r.options.versionPrefix == VersionPrefixForbidden && prefixed {
+				return microerror.Mask(fmt.Errorf("expected release notes for %s release %s to write version %s without a v prefix on the first line", r.provider, release.Name, token))
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateReleaseNotesLinks(r *run) error {
+	linkPattern := regexp.MustCompile(regexp.QuoteMeta(strings.TrimSuffix(r.options.repository, "/")) + `/tree/[^/\s]+/([^\s)#?]+)`)
+
+	releases, err := r.findReleases(false)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	for _, release := range releases {
+		releaseNotesData, err := r.readFile(filepath.Join(r.provider, release.Name, key.ReadmeFilename))
+		if err != nil {
+			return microerror.Mask(fmt.Errorf("missing file for %s release %s: %s", r.provider, release.Name, err))
+		}
+
+		// Check that links into the repository point at this release, not one of another
+		// provider or version copied from elsewhere.
+		for _, match := range linkPattern.FindAllStringSubmatch(string(releaseNotesData), -1) {
+			segments := strings.Split(strings.Trim(match[1], "/"), "/")
+			if segments[0] != r.provider {
+				return microerror.Mask(fmt.Errorf("release notes for %s release %s link to provider %s: %s", r.provider, release.Name, segments[0], match[0]))
+			}
+			if len(segments) > 1 && segments[1] != key.ArchivedDirectory && key.NormalizeVersion(segments[1]) != key.NormalizeVersion(release.Name) {
+				return microerror.Mask(fmt.Errorf("release notes for %s release %s link to release %s: %s", r.provider, release.Name, segments[1], match[0]))
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateReleaseNotesSections(r *run) error {
+	if len(r.options.requiredSections) == 0 {
+		return nil
+	}
+
+	releases, err := r.findReleases(false)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	for _, release := range releases {
+		releaseNotesData, err := r.readFile(filepath.Join(r.provider, release.Name, key.ReadmeFilename))
+		if err != nil {
+			return microerror.Mask(fmt.Errorf("missing file for %s release %s: %s", r.provider, release.Name, err))
+		}
+
+		headings := map[string]bool{}
+		for _, line := range strings.Split(string(releaseNotesData), "\n") {
+			if strings.HasPrefix(line, "#") {
+				headings[strings.TrimSpace(line)] = true
+			}
+		}
+
+		// Check that the release notes contain every required section heading.
+		for _, section := range r.options.requiredSections {
+			if !headings[section] {
+				return microerror.Mask(fmt.Errorf("expected release notes for %s release %s to contain section %q", r.provider, release.Name, section))
+			}
+		}
+	}
+
+	return nil
+}
+
+// releaseNotesDateFormats are the formats in which release notes may mention the release
+// date.
+var releaseNotesDateFormats = []string{
+	"2006-01-02",
+	"January 2, 2006",
+	"2 January 2006",
+	"Jan 2, 2006",
+}
+
+// validateReleaseNotesDates checks, when enabled with WithReleaseNotesDates, that the first or
+// second non-empty line of the release notes mentions the release date in one of
+// releaseNotesDateFormats.
+func validateReleaseNotesDates(r *run) error {
+	if !r.options.releaseNotesDates {
+		return nil
+	}
+
+	releases, err := r.findReleases(false)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	for _, release := range releases {
+		if release.Spec.Date == nil {
+			continue
+		}
+
+		releaseNotesData, err := r.readFile(filepath.Join(r.provider, release.Name, key.ReadmeFilename))
+		if err != nil {
+			return microerror.Mask(fmt.Errorf("missing file for %s release %s: %s", r.provider, release.Name, err))
+		}
+
+		var header []string
+		for _, line := range strings.Split(string(releaseNotesData), "\n") {
+			if strings.TrimSpace(line) != "" {
+				header = append(header, line)
+			}
+			if len(header) == 2 {
+				break
+			}
+		}
+
+		date := release.Spec.Date.UTC()
+		found := false
+		for _, line := range header {
+			for _, format := range releaseNotesDateFormats {
+				if strings.Contains(line, date.Format(format)) {
+					found = true
+				}
 			}
 		}
+		if !found {
+			return microerror.Mask(fmt.Errorf("expected release notes for %s release %s to mention the release date %s on the first or second line", r.provider, release.Name, date.Format(releaseNotesDateFormats[0])))
+		}
 	}
 
 	return nil
 }
 
-func validateReadme(fs filesystem.Filesystem, provider string) error {
+// versionsEqual returns whether the two versions are semver-equal, ignoring any `v` prefix.
+func versionsEqual(a string, b string) (bool, error) {
+	versionA, err := semver.NewVersion(a)
+	if err != nil {
+		return false, microerror.Mask(err)
+	}
+	versionB, err := semver.NewVersion(b)
+	if err != nil {
+		return false, microerror.Mask(err)
+	}
+	return versionA.Equal(versionB), nil
+}
+
+func validateReadme(r *run) error {
 	// Load the README so we can check links for each release.
 	var readmeContent string
 	{
-		readmeContentBytes, err := fs.ReadFile(key.ReadmeFilename)
+		readmeContentBytes, err := r.readFile(key.ReadmeFilename)
 		if err != nil {
 			return microerror.Mask(err)
 		}
 		readmeContent = string(readmeContentBytes)
 	}
 
-	releases, err := fs.FindReleases(provider, false)
+	releases, err := r.findAllReleases(false)
 	if err != nil {
 		return microerror.Mask(err)
 	}
 
 	for _, release := range releases {
-		// Check that the README links to the release.
-		if !strings.Contains(readmeContent, fmt.Sprintf("https://github.com/giantswarm/releaseclient/tree/master/%s/%s", provider, release.Name)) {
-			return microerror.Mask(fmt.Errorf("expected link in %s to %s release %s", key.ReadmeFilename, provider, release.Name))
+		if !r.stateSelected(release) {
+			continue
+		}
+
+		// Check that the README links to the release exactly once.
+		switch countLinks(readmeContent, releaseLink(r.options.repository, r.provider, release.Name)) {
+		case 0:
+			return microerror.Mask(fmt.Errorf("expected link in %s to %s release %s", key.ReadmeFilename, r.provider, release.Name))
+		case 1:
+		default:
+			return microerror.Mask(fmt.Errorf("expected a single link in %s to %s release %s, found duplicates", key.ReadmeFilename, r.provider, release.Name))
 		}
 	}
 
-	archived, err := fs.FindReleases(provider, true)
+	archived, err := r.findAllReleases(true)
 	if err != nil {
 		return microerror.Mask(err)
 	}
 
 	for _, release := range archived {
-		// Check that the README links to the release.
-		if !strings.Contains(readmeContent, fmt.Sprintf("https://github.com/giantswarm/releases/tree/master/%s/archived/%s", provider, release.Name)) {
-			return microerror.Mask(fmt.Errorf("expected link in %s to archived %s release %s", key.ReadmeFilename, provider, release.Name))
+		if !r.stateSelected(release) {
+			continue
+		}
+
+		// Check that the README links to the release exactly once.
+		switch countLinks(readmeContent, archivedReleaseLink(r.options.archivedLinkTemplate, r.options.repository, r.provider, release.Name)) {
+		case 0:
+			return microerror.Mask(fmt.Errorf("expected link in %s to archived %s release %s", key.ReadmeFilename, r.provider, release.Name))
+		case 1:
+		default:
+			return microerror.Mask(fmt.Errorf("expected a single link in %s to archived %s release %s, found duplicates", key.ReadmeFilename, r.provider, release.Name))
 		}
 	}
 
 	return nil
 }
 
-func validateReleasesAgainstCRD(fs filesystem.Filesystem, provider string) error {
-	releases, err := fs.FindReleases(provider, false)
+// countLinks returns how often the given link occurs in content. Occurrences continued by
+// further URL characters, like a link to v1.0.10 when counting v1.0.1, don't count, while a
+// period ending the sentence after the link doesn't continue it.
+func countLinks(content string, link string) int {
+	pattern := regexp.MustCompile(regexp.QuoteMeta(link) + `([^0-9A-Za-z._~+/-]|\.?$|\.[^0-9A-Za-z])`)
+	return len(pattern.FindAllStringIndex(content, -1))
+}
+
+// releaseLink returns the URL of the given release's directory in the repository.
+func releaseLink(repository string, provider string, release string) string {
+	return fmt.Sprintf("%s/tree/master/%s/%s", strings.TrimSuffix(repository, "/"), provider, release)
+}
+
+// archivedReleaseLink returns the URL of the given archived release's directory in the
+// repository by filling in the placeholders of the given template, see WithArchivedLinkTemplate.
+func archivedReleaseLink(template string, repository string, provider string, release string) string {
+	return strings.NewReplacer(
+		"{repository}", strings.TrimSuffix(repository, "/"),
+		"{provider}", provider,
+		"{release}", release,
+	).Replace(template)
+}
+
+func validateReleasesAgainstCRD(r *run) error {
+	releases, err := r.findReleases(false)
 	if err != nil {
 		return microerror.Mask(err)
 	}
 
+	archivedNames := map[string]bool{}
+	if r.options.archivedCRD {
+		archived, err := r.findReleases(true)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		for _, release := range archived {
+			archivedNames[release.Name] = true
+		}
+		releases = append(releases, archived...)
+	}
+
 	crd := v1alpha1.NewReleaseCRD()
 
+	selected := map[string]bool{}
+	for _, name := range r.options.crdVersions {
+		selected[name] = false
+	}
+
 	for _, crdVersion := range crd.Spec.Versions {
+		if len(r.options.crdVersions) > 0 {
+			if _, ok := selected[crdVersion.Name]; !ok {
+				r.options.logger.Debugf("skipping CRD version %s", crdVersion.Name)
+				continue
+			}
+			selected[crdVersion.Name] = true
+		}
+
 		var v apiextensions.CustomResourceValidation
 		// Convert the CRD validation into the version-independent form.
 		err := v1.Convert_v1_CustomResourceValidation_To_apiextensions_CustomResourceValidation(crdVersion.Schema, &v, nil)
@@ -164,101 +581,1406 @@ func validateReleasesAgainstCRD(fs filesystem.Filesystem, provider string) error
 		for _, release := range releases {
 			result := validator.Validate(release)
 			if len(result.Errors) > 0 {
-				message := fmt.Sprintf("invalid release: %#v\n", release)
+				message := fmt.Sprintf("%s release %s is invalid against CRD version %s\n", r.provider, release.Name, crdVersion.Name)
+				if archivedNames[release.Name] {
+					message = "archived " + message
+				}
 				for i, err := range result.Errors {
-					message += fmt.Sprintf("validation error %d: %#v\n", i, err)
+					message += fmt.Sprintf("validation error %d: %s\n", i, err)
 				}
+				return microerror.Mask(fmt.Errorf("%s", message))
 			}
 		}
 	}
 
+	for name, found := range selected {
+		if !found {
+			return microerror.Mask(fmt.Errorf("CRD version %s selected for validation not found", name))
+		}
+	}
+
 	return nil
 }
 
-func validateVersionBundle(fs filesystem.Filesystem, provider string) error {
-	releases, err := fs.FindReleases(provider, false)
+func validateAppComponentVersions(r *run) error {
+	releases, err := r.findReleases(false)
 	if err != nil {
 		return microerror.Mask(err)
 	}
 
-	// Ensure that releases are unique.
-	indexReleases := releasesToIndex(releases)
-	err = versionbundle.ValidateIndexReleases(indexReleases)
-	if err != nil {
-		return microerror.Mask(err)
+	for _, release := range releases {
+		componentVersions := map[string]string{}
+		for _, component := range release.Spec.Components {
+			componentVersions[component.Name] = component.Version
+		}
+
+		// Check that apps shipping a component of the release declare that component's version.
+		for _, app := range release.Spec.Apps {
+			if app.ComponentVersion == "" {
+				continue
+			}
+			componentVersion, ok := componentVersions[app.Name]
+			if !ok {
+				continue
+			}
+			if app.ComponentVersion != componentVersion {
+				return microerror.Mask(fmt.Errorf("app %s in %s release %s has component version %s but component %s is at version %s", app.Name, r.provider, release.Name, app.ComponentVersion, app.Name, componentVersion))
+			}
+		}
 	}
 
 	return nil
 }
 
-func validateKustomization(fs filesystem.Filesystem, provider string) error {
-	releases, err := fs.FindReleases(provider, false)
+// validateComponentVersionPrefix checks that the versions of components and apps follow the
+// `v` prefix convention set with WithComponentVersionPrefix.
+func validateComponentVersionPrefix(r *run) error {
+	if r.options.componentPrefix == VersionPrefixOptional {
+		return nil
+	}
+
+	releases, err := r.findReleases(false)
 	if err != nil {
 		return microerror.Mask(err)
 	}
 
-	providerResources := map[string]bool{}
-	{
-		var providerKustomization kustomizationFile
-		providerKustomizationData, err := fs.ReadFile(filepath.Join(provider, key.KustomizationFilename))
-		if err != nil {
-			return microerror.Mask(err)
-		}
-		err = yaml.UnmarshalStrict(providerKustomizationData, &providerKustomization)
-		if err != nil {
-			return microerror.Mask(err)
+	for _, release := range releases {
+		type entry struct {
+			kind    string
+			name    string
+			version string
 		}
-		for _, resource := range providerKustomization.Resources {
-			providerResources[resource] = false
+		var entries []entry
+		for _, app := range release.Spec.Apps {
+			entries = append(entries, entry{kind: "app", name: app.Name, version: app.Version})
+			if app.ComponentVersion != "" {
+				entries = append(entries, entry{kind: "component version of app", name: app.Name, version: app.ComponentVersion})
+			}
 		}
-	}
-
-	for _, release := range releases {
-		// Check that the release is registered in the main provider kustomization.yaml resources.
-		if _, ok := providerResources[release.Name]; !ok {
-			return microerror.Mask(fmt.Errorf("release %s not registered in %s/%s", release.Name, provider, key.KustomizationFilename))
+		for _, component := range release.Spec.Components {
+			entries = append(entries, entry{kind: "component", name: component.Name, version: component.Version})
 		}
-		providerResources[release.Name] = true
 
-		// Check that the release-specific kustomization.yaml file points to the release manifest.
-		{
-			releaseKustomizationData, err := fs.ReadFile(filepath.Join(provider, release.Name, key.KustomizationFilename))
-			if err != nil {
-				return microerror.Mask(fmt.Errorf("missing file for %s release %s: %s", provider, release.Name, err))
+		for _, e := range entries {
+			prefixed := strings.HasPrefix(e.version, "v")
+			if r.options.componentPrefix == VersionPrefixRequired && !prefixed {
+				return microerror.Mask(fmt.Errorf("%s %s in %s release %s must write version %s with a v prefix", e.kind, e.name, r.provider, release.Name, e.version))
 			}
-			var releaseKustomization kustomizationFile
-			err = yaml.UnmarshalStrict(releaseKustomizationData, &releaseKustomization)
-			if len(releaseKustomization.Resources) != 1 || releaseKustomization.Resources[0] != key.ReleaseFilename {
-				return microerror.Mask(fmt.Errorf("%s for %s release %s should contain only one resource, \"%s\"", key.KustomizationFilename, provider, release.Name, key.ReleaseFilename))
+			if r.options.componentPrefix == VersionPrefixForbidden && prefixed {
+				return microerror.Mask(fmt.Errorf("%s %s in %s release %s must write version %s without a v prefix", e.kind, e.name, r.provider, release.Name, e.version))
 			}
 		}
 	}
 
-	// Check for extra resources in provider kustomization.yaml that don't have a corresponding release.
-	for release, processed := range providerResources {
-		if !processed {
-			return microerror.Mask(fmt.Errorf("release %s registered in %s/%s resources but not found", release, provider, key.KustomizationFilename))
-		}
-	}
-
 	return nil
 }
 
-func Validate(fs filesystem.Filesystem, provider string) error {
-	validations := []func(fs filesystem.Filesystem, provider string) error {
-		validateRequests,
-		validateReleaseNotes,
-		validateReadme,
-		validateReleasesAgainstCRD,
-		validateVersionBundle,
-		validateKustomization,
+// validateNameCasing checks that apps and components of a release referring to each other
+// spell their names the same way, as they are correlated by exact name.
+func validateNameCasing(r *run) error {
+	releases, err := r.findReleases(false)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	for _, release := range releases {
+		componentNames := map[string]string{}
+		for _, component := range release.Spec.Components {
+			componentNames[strings.ToLower(component.Name)] = component.Name
+		}
+
+		for _, app := range release.Spec.Apps {
+			componentName, ok := componentNames[strings.ToLower(app.Name)]
+			if ok && componentName != app.Name {
+				return microerror.Mask(fmt.Errorf("app %s in %s release %s differs only in casing from component %s", app.Name, r.provider, release.Name, componentName))
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateDuplicateKeys(r *run) error {
+	releases, err := r.findReleases(false)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	for _, release := range releases {
+		path := filepath.Join(r.provider, release.Name, key.ReleaseFilename)
+		data, err := r.readFile(path)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		// Releases are decoded leniently, taking the last value of duplicate keys. Decoding
+		// strictly into a generic value reports duplicates at any level.
+		var manifest interface{}
+		err = yaml.UnmarshalStrict(data, &manifest)
+		if err != nil {
+			return microerror.Mask(fmt.Errorf("release manifest %s must not contain duplicate keys: %s", path, err))
+		}
+	}
+
+	return nil
+}
+
+func validateAppCatalogs(r *run) error {
+	allowed := map[string]bool{}
+	for _, catalog := range r.options.allowedCatalogs {
+		allowed[catalog] = true
+	}
+
+	releases, err := r.findReleases(false)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	for _, release := range releases {
+		// v1alpha1.ReleaseSpecApp has no catalog field so the manifest is decoded again.
+		data, err := r.readFile(filepath.Join(r.provider, release.Name, key.ReleaseFilename))
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		var manifest releaseAppsFile
+		err = yaml.Unmarshal(data, &manifest)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		for _, app := range manifest.Spec.Apps {
+			if app.Catalog != "" && !allowed[app.Catalog] {
+				return microerror.Mask(fmt.Errorf("app %s in %s release %s references unknown catalog %s, allowed are %s", app.Name, r.provider, release.Name, app.Catalog, strings.Join(r.options.allowedCatalogs, ", ")))
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateRequiredApps checks that every active release contains the apps required with
+// WithRequiredApps.
+func validateRequiredApps(r *run) error {
+	releases, err := r.findReleases(false)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	for _, release := range releases {
+		if release.Spec.State != v1alpha1.StateActive {
+			continue
+		}
+
+		apps := map[string]bool{}
+		for _, app := range release.Spec.Apps {
+			apps[app.Name] = true
+		}
+		for _, name := range r.options.requiredApps {
+			if !apps[name] {
+				return microerror.Mask(fmt.Errorf("active %s release %s must contain app %s", r.provider, release.Name, name))
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateMinimumVersions checks that no active release ships a component or app below the
+// minimum version set with WithMinimumVersions.
+func validateMinimumVersions(r *run) error {
+	if len(r.options.minimumVersions) == 0 {
+		return nil
+	}
+
+	minimums := map[string]*semver.Version{}
+	for name, version := range r.options.minimumVersions {
+		minimum, err := semver.NewVersion(version)
+		if err != nil {
+			return microerror.Mask(fmt.Errorf("minimum version %q for %s must be a valid semver version: %s", version, name, err))
+		}
+		minimums[name] = minimum
+	}
+
+	releases, err := r.findReleases(false)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	for _, release := range releases {
+		if release.Spec.State != v1alpha1.StateActive {
+			continue
+		}
+
+		versions := map[string]string{}
+		for _, app := range release.Spec.Apps {
+			versions[app.Name] = app.Version
+		}
+		for _, component := range release.Spec.Components {
+			versions[component.Name] = component.Version
+		}
+
+		var names []string
+		for name := range versions {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			minimum, ok := minimums[name]
+			if !ok {
+				continue
+			}
+			version, err := semver.NewVersion(versions[name])
+			if err != nil {
+				return microerror.Mask(fmt.Errorf("%s in %s release %s has invalid version %q: %s", name, r.provider, release.Name, versions[name], err))
+			}
+			if version.LessThan(minimum) {
+				return microerror.Mask(fmt.Errorf("active %s release %s ships %s %s which is below the supported minimum %s", r.provider, release.Name, name, versions[name], r.options.minimumVersions[name]))
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateReleaseLabels checks that every release carries the labels required with
+// WithRequiredLabels and that its provider label, if set, names the provider it is in.
+func validateReleaseLabels(r *run) error {
+	releases, err := r.findReleases(false)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	for _, release := range releases {
+		for _, label := range r.options.requiredLabels {
+			if _, ok := release.Labels[label]; !ok {
+				return microerror.Mask(fmt.Errorf("%s release %s must have label %s", r.provider, release.Name, label))
+			}
+		}
+
+		provider, ok := release.Labels[key.ProviderLabel]
+		if ok && provider != r.provider {
+			return microerror.Mask(fmt.Errorf("%s release %s has label %s=%s which doesn't match its provider", r.provider, release.Name, key.ProviderLabel, provider))
+		}
+	}
+
+	return nil
+}
+
+func validateReleaseNames(r *run) error {
+	releases, err := r.findReleases(false)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	for _, release := range releases {
+		_, err := parseVersion(release.Name)
+		if err != nil {
+			return microerror.Mask(fmt.Errorf("name of %s release %s must be a valid semver version: %s", r.provider, release.Name, err))
+		}
+		if r.options.releaseNamePattern != nil && !r.options.releaseNamePattern.MatchString(release.Name) {
+			return microerror.Mask(fmt.Errorf("name of %s release %s must match %s", r.provider, release.Name, r.options.releaseNamePattern))
+		}
+	}
+
+	return nil
+}
+
+func validateVersions(r *run) error {
+	releases, err := r.findReleases(false)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	for _, release := range releases {
+		// Check that every app and component version is valid semver, whether or not a request
+		// would parse it.
+		for _, app := range release.Spec.Apps {
+			_, err := parseVersion(app.Version)
+			if err != nil {
+				return microerror.Mask(fmt.Errorf("app %s in %s release %s has invalid version %q: %s", app.Name, r.provider, release.Name, app.Version, err))
+			}
+			if app.ComponentVersion == "" {
+				continue
+			}
+			_, err = parseVersion(app.ComponentVersion)
+			if err != nil {
+				return microerror.Mask(fmt.Errorf("app %s in %s release %s has invalid component version %q: %s", app.Name, r.provider, release.Name, app.ComponentVersion, err))
+			}
+		}
+		for _, component := range release.Spec.Components {
+			_, err := parseVersion(component.Version)
+			if err != nil {
+				return microerror.Mask(fmt.Errorf("component %s in %s release %s has invalid version %q: %s", component.Name, r.provider, release.Name, component.Version, err))
+			}
+		}
+	}
+
+	return nil
+}
+
+// parseVersion parses a complete semver version with an optional `v` prefix. Unlike
+// semver.NewVersion it rejects partial versions like 1.2.
+func parseVersion(version string) (*semver.Version, error) {
+	v, err := semver.StrictNewVersion(strings.TrimPrefix(version, "v"))
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	return v, nil
+}
+
+// validateFutureDates warns about active releases dated further in the future than allowed by
+// WithMaxFutureDays, which usually means the year is wrong.
+func validateFutureDates(r *run) error {
+	releases, err := r.findReleases(false)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	limit := r.options.now().AddDate(0, 0, r.options.maxFutureDays)
+	for _, release := range releases {
+		if release.Spec.State != v1alpha1.StateActive || release.Spec.Date == nil {
+			continue
+		}
+		if release.Spec.Date.Time.After(limit) {
+			r.warnf(release.Name, "active %s release %s is dated %s, more than %d days in the future", r.provider, release.Name, release.Spec.Date.Format("2006-01-02"), r.options.maxFutureDays)
+		}
+	}
+
+	return nil
+}
+
+// validateDateFormat checks that release dates are written in UTC as RFC 3339 without
+// fractional seconds, e.g. 2020-09-01T12:00:00Z, so that diffs stay clean. metav1.Time
+// requires a time component, a date alone can't be decoded.
+func validateDateFormat(r *run) error {
+	releases, err := r.findReleases(false)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	for _, release := range releases {
+		// The parsed time no longer tells how the date was written so the manifest is decoded again.
+		data, err := r.readFile(filepath.Join(r.provider, release.Name, key.ReleaseFilename))
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		var manifest releaseDateFile
+		err = yaml.Unmarshal(data, &manifest)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		if manifest.Spec.Date == "" {
+			continue
+		}
+
+		date, err := time.Parse(time.RFC3339, manifest.Spec.Date)
+		if err != nil {
+			return microerror.Mask(fmt.Errorf("%s release %s has date %q, expected a UTC timestamp like 2020-09-01T12:00:00Z: %s", r.provider, release.Name, manifest.Spec.Date, err))
+		}
+		if canonical := date.UTC().Format(time.RFC3339); canonical != manifest.Spec.Date {
+			return microerror.Mask(fmt.Errorf("%s release %s has date %q, expected canonical UTC timestamp %q", r.provider, release.Name, manifest.Spec.Date, canonical))
+		}
+	}
+
+	return nil
+}
+
+// validateSpecVersion checks that a spec.version in a release manifest, if present, agrees
+// with the release name with or without the `v` prefix, see key.NormalizeVersion.
+func validateSpecVersion(r *run) error {
+	releases, err := r.findReleases(false)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	for _, release := range releases {
+		// v1alpha1.ReleaseSpec has no version field so the manifest is decoded again.
+		data, err := r.readFile(filepath.Join(r.provider, release.Name, key.ReleaseFilename))
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		var manifest releaseVersionFile
+		err = yaml.Unmarshal(data, &manifest)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		if manifest.Spec.Version != "" && key.NormalizeVersion(manifest.Spec.Version) != key.NormalizeVersion(release.Name) {
+			return microerror.Mask(fmt.Errorf("%s release %s has spec.version %s which does not match its name", r.provider, release.Name, manifest.Spec.Version))
+		}
+	}
+
+	return nil
+}
+
+// validateSunsetDates checks that deprecated releases carry a sunset date in the future when
+// enabled with WithSunsetDates.
+func validateSunsetDates(r *run) error {
+	if !r.options.sunsetDates {
+		return nil
+	}
+
+	releases, err := r.findReleases(false)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	for _, release := range releases {
+		if release.Spec.State != v1alpha1.StateDeprecated {
+			continue
+		}
+		sunset, err := key.SunsetDate(release)
+		if err != nil {
+			return microerror.Mask(fmt.Errorf("deprecated %s release %s has invalid %s annotation, expected a date like %s: %s", r.provider, release.Name, key.SunsetAnnotation, key.SunsetDateFormat, err))
+		}
+		if sunset == nil {
+			return microerror.Mask(fmt.Errorf("deprecated %s release %s must have a sunset date in spec.endOfLifeDate or annotation %s", r.provider, release.Name, key.SunsetAnnotation))
+		}
+		if !sunset.After(r.options.now()) {
+			return microerror.Mask(fmt.Errorf("deprecated %s release %s has sunset date %s which is not in the future", r.provider, release.Name, sunset.Format(key.SunsetDateFormat)))
+		}
+	}
+
+	return nil
+}
+
+// validateComponentDowngrades warns about components whose version is lower than in the
+// release's immediate semver predecessor.
+func validateComponentDowngrades(r *run) error {
+	if !r.options.downgradeWarnings {
+		return nil
+	}
+
+	all, err := r.findAllReleases(false)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	err = sortReleases(all)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	releases, err := r.findReleases(false)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	selected := map[string]bool{}
+	for _, release := range releases {
+		selected[key.NormalizeVersion(release.Name)] = true
+	}
+
+	for i := 1; i < len(all); i++ {
+		previous, release := all[i-1], all[i]
+		if !selected[key.NormalizeVersion(release.Name)] {
+			continue
+		}
+
+		previousVersions := map[string]string{}
+		for _, component := range previous.Spec.Components {
+			previousVersions[component.Name] = component.Version
+		}
+		for _, component := range release.Spec.Components {
+			previousVersion, ok := previousVersions[component.Name]
+			if !ok {
+				continue
+			}
+			// Invalid versions are reported by validateVersions.
+			before, err := semver.NewVersion(previousVersion)
+			if err != nil {
+				continue
+			}
+			after, err := semver.NewVersion(component.Version)
+			if err != nil {
+				continue
+			}
+			if after.LessThan(before) {
+				r.warnf(release.Name, "component %s is downgraded from %s in %s to %s in %s release %s", component.Name, previousVersion, previous.Name, component.Version, r.provider, release.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateActiveReleaseCount warns when the provider has more active releases than allowed
+// with WithMaxActiveReleases.
+func validateActiveReleaseCount(r *run) error {
+	if r.options.maxActiveReleases <= 0 {
+		return nil
+	}
+
+	releases, err := r.findAllReleases(false)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	var active int
+	for _, release := range releases {
+		if release.Spec.State == v1alpha1.StateActive {
+			active++
+		}
+	}
+	if active > r.options.maxActiveReleases {
+		r.warnf("", "%s has %d active releases, more than the maximum of %d, consider archiving old ones", r.provider, active, r.options.maxActiveReleases)
+	}
+
+	return nil
+}
+
+// validatePrereleaseStates warns about pre-releases like v1.2.0-beta being active, as they
+// should usually be wip.
+func validatePrereleaseStates(r *run) error {
+	releases, err := r.findReleases(false)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	for _, release := range releases {
+		version, err := semver.NewVersion(release.Name)
+		if err != nil {
+			// Invalid release names are reported by other validators.
+			continue
+		}
+		if version.Prerelease() != "" && release.Spec.State == v1alpha1.StateActive {
+			r.warnf(release.Name, "%s release %s is a pre-release but in state %s, expected %s", r.provider, release.Name, release.Spec.State, v1alpha1.StateWIP)
+		}
+	}
+
+	return nil
+}
+
+// legalStateTransitions maps each release state to the states a release may move to from it.
+// Releases only ever move forward from wip over active to deprecated.
+var legalStateTransitions = map[v1alpha1.ReleaseState][]v1alpha1.ReleaseState{
+	v1alpha1.StateWIP:        {v1alpha1.StateWIP, v1alpha1.StateActive, v1alpha1.StateDeprecated},
+	v1alpha1.StateActive:     {v1alpha1.StateActive, v1alpha1.StateDeprecated},
+	v1alpha1.StateDeprecated: {v1alpha1.StateDeprecated},
+}
+
+func validateStateTransitions(r *run) error {
+	if len(r.options.baseReleases) == 0 {
+		return nil
+	}
+
+	baseStates := map[string]v1alpha1.ReleaseState{}
+	for _, release := range r.options.baseReleases {
+		baseStates[key.NormalizeVersion(release.Name)] = release.Spec.State
+	}
+
+	releases, err := r.findReleases(false)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	for _, release := range releases {
+		base, ok := baseStates[key.NormalizeVersion(release.Name)]
+		if !ok {
+			continue
+		}
+
+		legal := false
+		for _, state := range legalStateTransitions[base] {
+			if state == release.Spec.State {
+				legal = true
+				break
+			}
+		}
+		if !legal {
+			return microerror.Mask(fmt.Errorf("%s release %s must not change state from %s to %s", r.provider, release.Name, base, release.Spec.State))
+		}
+	}
+
+	return nil
+}
+
+// validatePredecessorContents warns about components and apps which were shipped in a
+// release's immediate semver predecessor but are missing from the release itself.
+func validatePredecessorContents(r *run) error {
+	all, err := r.findAllReleases(false)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	err = sortReleases(all)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	releases, err := r.findReleases(false)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	selected := map[string]bool{}
+	for _, release := range releases {
+		selected[key.NormalizeVersion(release.Name)] = true
+	}
+
+	for i := 1; i < len(all); i++ {
+		previous, release := all[i-1], all[i]
+		if !selected[key.NormalizeVersion(release.Name)] {
+			continue
+		}
+
+		components := map[string]bool{}
+		for _, component := range release.Spec.Components {
+			components[component.Name] = true
+		}
+		for _, component := range previous.Spec.Components {
+			if !components[component.Name] {
+				r.warnf(release.Name, "component %s is missing from %s release %s but was shipped in %s", component.Name, r.provider, release.Name, previous.Name)
+			}
+		}
+
+		apps := map[string]bool{}
+		for _, app := range release.Spec.Apps {
+			apps[app.Name] = true
+		}
+		for _, app := range previous.Spec.Apps {
+			if !apps[app.Name] {
+				r.warnf(release.Name, "app %s is missing from %s release %s but was shipped in %s", app.Name, r.provider, release.Name, previous.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateVersionBundle(r *run) error {
+	releases, err := r.findAllReleases(false)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	// Ensure that releases are unique.
+	indexReleases := releasesToIndex(releases)
+	err = versionbundle.ValidateIndexReleases(indexReleases)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+// validateActiveReleaseOrder warns about active releases sharing a date, as their order in
+// the release index is ambiguous then.
+func validateActiveReleaseOrder(r *run) error {
+	releases, err := r.findAllReleases(false)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	dated := map[int64]string{}
+	for _, release := range releasesToIndex(releases) {
+		if !release.Active || release.Date.IsZero() {
+			continue
+		}
+
+		other, ok := dated[release.Date.UnixNano()]
+		if ok {
+			r.warnf(release.Version, "active %s releases %s and %s share the date %s, their order is ambiguous", r.provider, other, release.Version, release.Date.UTC().Format(time.RFC3339))
+			continue
+		}
+		dated[release.Date.UnixNano()] = release.Version
+	}
+
+	return nil
+}
+
+// validateIdenticalReleases warns about active releases shipping exactly the same component
+// and app versions, which usually means one of them should have been an update.
+func validateIdenticalReleases(r *run) error {
+	releases, err := r.findAllReleases(false)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	seen := map[string]string{}
+	for _, release := range releases {
+		if release.Spec.State != v1alpha1.StateActive {
+			continue
+		}
+
+		var versions []string
+		for _, app := range release.Spec.Apps {
+			versions = append(versions, fmt.Sprintf("app %s %s", app.Name, app.Version))
+		}
+		for _, component := range release.Spec.Components {
+			versions = append(versions, fmt.Sprintf("component %s %s", component.Name, component.Version))
+		}
+		sort.Strings(versions)
+		signature := strings.Join(versions, "\n")
+
+		other, ok := seen[signature]
+		if ok {
+			r.warnf(release.Name, "active %s releases %s and %s ship identical component and app versions", r.provider, other, release.Name)
+			continue
+		}
+		seen[signature] = release.Name
+	}
+
+	return nil
+}
+
+// validateReleaseKustomization checks that the kustomization.yaml of the given release only
+// lists the release manifest and that its transformers are valid.
+func validateReleaseKustomization(r *run, release string, data []byte) error {
+	path := filepath.Join(r.provider, release, key.KustomizationFilename)
+
+	var releaseKustomization kustomizationFile
+	err := yaml.UnmarshalStrict(data, &releaseKustomization)
+	if err != nil {
+		return microerror.Mask(fmt.Errorf("%s for %s release %s is invalid: %s", key.KustomizationFilename, r.provider, release, err))
+	}
+	if len(releaseKustomization.Resources) != 1 || releaseKustomization.Resources[0] != key.ReleaseFilename {
+		return microerror.Mask(fmt.Errorf("%s for %s release %s should contain only one resource, \"%s\"", key.KustomizationFilename, r.provider, release, key.ReleaseFilename))
+	}
+	err = validateTransformers(r, path, releaseKustomization)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+func validateKustomization(r *run) error {
+	releases, err := r.findAllReleases(false)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	providerResources, err := loadKustomizationResources(r, filepath.Join(r.provider, key.KustomizationFilename))
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	// Missing release kustomization.yaml files are collected so that all of them are reported
+	// at once, before the first other problem found on the way.
+	var missing []string
+	var firstErr error
+	for _, release := range releases {
+		// Check that the release is registered in the main provider kustomization.yaml resources.
+		if _, ok := providerResources[release.Name]; !ok {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("release %s not registered in %s/%s", release.Name, r.provider, key.KustomizationFilename)
+			}
+			continue
+		}
+		providerResources[release.Name] = true
+
+		// Check that the release-specific kustomization.yaml file points to the release manifest.
+		releaseKustomizationData, err := r.readFile(filepath.Join(r.provider, release.Name, key.KustomizationFilename))
+		if err != nil {
+			missing = append(missing, fmt.Sprintf("missing file for %s release %s: %s", r.provider, release.Name, err))
+			continue
+		}
+		if firstErr != nil {
+			continue
+		}
+		firstErr = validateReleaseKustomization(r, release.Name, releaseKustomizationData)
+	}
+	if len(missing) > 0 {
+		return microerror.Mask(fmt.Errorf("%s", strings.Join(missing, "\n")))
+	}
+	if firstErr != nil {
+		return microerror.Mask(firstErr)
+	}
+
+	archived, err := r.findAllReleases(true)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	// Archived releases are registered in their own kustomization.yaml and must never be part of the active one.
+	if len(archived) > 0 {
+		archivedResources, err := loadKustomizationResources(r, filepath.Join(r.provider, key.ArchivedDirectory, key.KustomizationFilename))
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		for _, release := range archived {
+			if _, ok := providerResources[release.Name]; ok {
+				return microerror.Mask(fmt.Errorf("archived release %s must not be registered in %s/%s", release.Name, r.provider, key.KustomizationFilename))
+			}
+			if _, ok := archivedResources[release.Name]; !ok {
+				return microerror.Mask(fmt.Errorf("archived release %s not registered in %s/%s/%s", release.Name, r.provider, key.ArchivedDirectory, key.KustomizationFilename))
+			}
+			archivedResources[release.Name] = true
+		}
+
+		for release, processed := range archivedResources {
+			if processed {
+				continue
+			}
+			if _, ok := providerResources[release]; ok {
+				return microerror.Mask(fmt.Errorf("active release %s must not be registered in %s/%s/%s", release, r.provider, key.ArchivedDirectory, key.KustomizationFilename))
+			}
+			return microerror.Mask(fmt.Errorf("release %s registered in %s/%s/%s resources but not found", release, r.provider, key.ArchivedDirectory, key.KustomizationFilename))
+		}
+	}
+
+	// Check for extra resources in provider kustomization.yaml that don't have a corresponding release.
+	for release, processed := range providerResources {
+		if !processed {
+			return microerror.Mask(fmt.Errorf("release %s registered in %s/%s resources but not found", release, r.provider, key.KustomizationFilename))
+		}
+	}
+
+	return nil
+}
+
+// sortReleases sorts the given releases by the semver version in their names.
+func sortReleases(releases []v1alpha1.Release) error {
+	versions := map[string]*semver.Version{}
+	for _, release := range releases {
+		version, err := semver.NewVersion(release.Name)
+		if err != nil {
+			return microerror.Mask(fmt.Errorf("release names must be valid semver: %s: %s", err, release.Name))
+		}
+		versions[release.Name] = version
+	}
+
+	sort.Slice(releases, func(i, j int) bool {
+		return versions[releases[i].Name].LessThan(versions[releases[j].Name])
+	})
+
+	return nil
+}
+
+func validateKustomizationTypes(r *run) error {
+	releases, err := r.findReleases(false)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	paths := []string{filepath.Join(r.provider, key.KustomizationFilename)}
+	for _, release := range releases {
+		paths = append(paths, filepath.Join(r.provider, release.Name, key.KustomizationFilename))
+	}
+
+	for _, path := range paths {
+		kustomization, err := loadKustomization(r, path)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		// Check that kustomize recognizes the file as a kustomization.
+		if kustomization.APIVersion != key.KustomizationAPIVersion {
+			return microerror.Mask(fmt.Errorf("expected apiVersion %s in %s, got %q", key.KustomizationAPIVersion, path, kustomization.APIVersion))
+		}
+		if kustomization.Kind != key.KustomizationKind {
+			return microerror.Mask(fmt.Errorf("expected kind %s in %s, got %q", key.KustomizationKind, path, kustomization.Kind))
+		}
+	}
+
+	return nil
+}
+
+func validateAnnotationPrefixes(r *run) error {
+	releases, err := r.findReleases(false)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	paths := []string{filepath.Join(r.provider, key.KustomizationFilename)}
+	for _, release := range releases {
+		paths = append(paths, filepath.Join(r.provider, release.Name, key.KustomizationFilename))
+	}
+
+	for _, path := range paths {
+		kustomization, err := loadKustomization(r, path)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		// Check that every common annotation uses one of the allowed prefixes.
+		var keys []string
+		for k := range kustomization.CommonAnnotations {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if !hasAnyPrefix(k, r.options.annotationPrefixes) {
+				return microerror.Mask(fmt.Errorf("common annotation %s in %s must use one of the prefixes %s", k, path, strings.Join(r.options.annotationPrefixes, ", ")))
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateRepositoryAnnotation(r *run) error {
+	if r.options.repositoryAnnotation == "" {
+		return nil
+	}
+
+	path := filepath.Join(r.provider, key.KustomizationFilename)
+	kustomization, err := loadKustomization(r, path)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	value, ok := kustomization.CommonAnnotations[r.options.repositoryAnnotation]
+	if !ok {
+		return microerror.Mask(fmt.Errorf("common annotation %s missing in %s", r.options.repositoryAnnotation, path))
+	}
+	if value != r.options.repository {
+		return microerror.Mask(fmt.Errorf("common annotation %s in %s must be %s, got %q", r.options.repositoryAnnotation, path, r.options.repository, value))
+	}
+
+	return nil
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// loadKustomization reads and parses the kustomization.yaml at the given path.
+func loadKustomization(r *run, path string) (kustomizationFile, error) {
+	data, err := r.readFile(path)
+	if err != nil {
+		return kustomizationFile{}, microerror.Mask(err)
+	}
+
+	var kustomization kustomizationFile
+	err = yaml.UnmarshalStrict(data, &kustomization)
+	if err != nil {
+		return kustomizationFile{}, microerror.Mask(err)
+	}
+
+	return kustomization, nil
+}
+
+// loadKustomizationResources reads the kustomization.yaml at the given path and returns its
+// resources as a map, with every resource marked as not yet processed. Resources listed more
+// than once are an error.
+func loadKustomizationResources(r *run, path string) (map[string]bool, error) {
+	kustomization, err := loadKustomization(r, path)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	err = validateTransformers(r, path, kustomization)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	resources := map[string]bool{}
+	for _, resource := range kustomization.Resources {
+		name := normalizeResource(resource)
+		if _, ok := resources[name]; ok {
+			return nil, microerror.Mask(fmt.Errorf("resource %s listed more than once in %s", resource, path))
+		}
+		resources[name] = false
+	}
+
+	return resources, nil
+}
+
+// normalizeResource turns a kustomization resource like `./v1.2.0/` into the directory name it
+// refers to, e.g. `v1.2.0`.
+func normalizeResource(resource string) string {
+	return path.Clean(filepath.ToSlash(resource))
+}
+
+// validateTransformers checks that every transformer referenced by the kustomization.yaml at
+// the given path exists.
+func validateTransformers(r *run, path string, kustomization kustomizationFile) error {
+	for _, transformer := range kustomization.Transformers {
+		// Like kustomize, resolve transformers relative to the kustomization's directory.
+		resolved := filepath.Join(filepath.Dir(path), filepath.FromSlash(transformer))
+		if !strings.HasPrefix(resolved, r.provider+string(filepath.Separator)) {
+			return microerror.Mask(fmt.Errorf("transformer %s referenced in %s resolves to %s outside of provider directory %s", transformer, path, filepath.ToSlash(resolved), r.provider))
+		}
+
+		exists, err := r.exists(resolved)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		if !exists {
+			return microerror.Mask(fmt.Errorf("transformer %s referenced in %s not found at %s", transformer, path, filepath.ToSlash(resolved)))
+		}
+	}
+
+	return nil
+}
+
+// versionTokenPattern matches the first semver version, with or without `v` prefix, in a line.
+var versionTokenPattern = regexp.MustCompile(`v?\d+\.\d+\.\d+(-[0-9A-Za-z.-]+)?(\+[0-9A-Za-z.-]+)?`)
+
+var validators = []validator{
+	{name: "provider-name", validate: validateProviderName},
+	{name: "release-directories", validate: validateReleaseDirectories},
+	{name: "release-directory-case", validate: validateReleaseDirectoryCase},
+	{name: "requests", validate: validateRequests},
+	{name: "requests-canonical", validate: validateRequestsCanonical},
+	{name: "requests-order", validate: validateRequestsOrder},
+	{name: "redundant-exceptions", validate: validateRedundantExceptions},
+	{name: "retired-provider-requests", validate: validateRetiredProviderRequests},
+	{name: "release-notes", validate: validateReleaseNotes},
+	{name: "release-notes-sections", validate: validateReleaseNotesSections},
+	{name: "release-notes-dates", validate: validateReleaseNotesDates},
+	{name: "release-notes-links", validate: validateReleaseNotesLinks},
+	{name: "readme", validate: validateReadme},
+	{name: "release-names", validate: validateReleaseNames},
+	{name: "crd", validate: validateReleasesAgainstCRD},
+	{name: "duplicate-keys", validate: validateDuplicateKeys},
+	{name: "versions", validate: validateVersions},
+	{name: "app-component-versions", validate: validateAppComponentVersions},
+	{name: "component-version-prefix", validate: validateComponentVersionPrefix},
+	{name: "name-casing", validate: validateNameCasing},
+	{name: "app-catalogs", validate: validateAppCatalogs},
+	{name: "required-apps", validate: validateRequiredApps},
+	{name: "minimum-versions", validate: validateMinimumVersions},
+	{name: "release-labels", validate: validateReleaseLabels},
+	{name: "future-dates", validate: validateFutureDates},
+	{name: "date-format", validate: validateDateFormat},
+	{name: "spec-version", validate: validateSpecVersion},
+	{name: "sunset-dates", validate: validateSunsetDates},
+	{name: "predecessor-contents", validate: validatePredecessorContents},
+	{name: "component-downgrades", validate: validateComponentDowngrades},
+	{name: "state-transitions", validate: validateStateTransitions},
+	{name: "prerelease-states", validate: validatePrereleaseStates},
+	{name: "active-release-count", validate: validateActiveReleaseCount},
+	{name: "version-bundle", validate: validateVersionBundle},
+	{name: "active-release-order", validate: validateActiveReleaseOrder},
+	{name: "identical-releases", validate: validateIdenticalReleases},
+	{name: "kustomization", validate: validateKustomization},
+	{name: "kustomization-types", validate: validateKustomizationTypes},
+	{name: "annotation-prefixes", validate: validateAnnotationPrefixes},
+	{name: "repository-annotation", validate: validateRepositoryAnnotation},
+}
+
+// Validate runs all validators for the given provider and returns the first error found.
+// Warnings never make it fail, use ValidateDetailed to inspect them.
+func Validate(fs filesystem.Filesystem, provider string, opts ...Option) error {
+	r := newRun(fs, provider, opts...)
+	err := r.execute(validators, true)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+// ValidateWithReporter validates the given provider like Validate while sending progress
+// events to reporter. The validators which only need a release manifest, see
+// releaseValidators, run once per release between ReleaseStarted and ReleaseFinished events,
+// followed by the remaining validators for the provider as a whole. A nil reporter is
+// treated as a no-op.
+func ValidateWithReporter(fs filesystem.Filesystem, provider string, reporter Reporter, opts ...Option) error {
+	r := newRun(fs, provider, opts...)
+	if reporter != nil {
+		r.reporter = reporter
+	}
+
+	exists, err := r.exists(r.provider)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	if !exists {
+		return microerror.Maskf(providerNotFoundError, "provider directory %s does not exist", r.provider)
+	}
+
+	err = r.executeByRelease(validators, true)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+// ValidateDetailed runs all validators for the given provider and returns every finding
+// instead of stopping at the first error.
+func ValidateDetailed(fs filesystem.Filesystem, provider string, opts ...Option) ([]ValidationResult, error) {
+	r := newRun(fs, provider, opts...)
+	err := r.execute(validators, false)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return r.results, nil
+}
+
+// ValidateAllProviders discovers the provider directories at the root of the repository with
+// filesystem.ListProviders and runs ValidateDetailed for each of them. The results are grouped
+// by provider.
+func ValidateAllProviders(fs filesystem.Filesystem, opts ...Option) (map[string][]ValidationResult, error) {
+	results, err := ValidateProviders(fs, nil, opts...)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return results, nil
+}
+
+// ValidateProviders runs ValidateDetailed for every provider like ValidateAllProviders. The
+// options in configs for a provider, e.g. WithRequiredApps or WithSkippedValidators, are
+// applied after the common opts so that rules can vary by provider. A config for a provider
+// which does not exist is an error.
+func ValidateProviders(fs filesystem.Filesystem, configs map[string][]Option, opts ...Option) (map[string][]ValidationResult, error) {
+	providers, err := filesystem.ListProviders(fs)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	found := map[string]bool{}
+	for _, provider := range providers {
+		found[provider] = true
+	}
+	var configured []string
+	for provider := range configs {
+		configured = append(configured, provider)
+	}
+	sort.Strings(configured)
+	for _, provider := range configured {
+		if !found[provider] {
+			return nil, microerror.Maskf(providerNotFoundError, "configuration given for provider %s which does not exist", provider)
+		}
+	}
+
+	results := map[string][]ValidationResult{}
+	for _, provider := range providers {
+		providerOpts := append(append([]Option{}, opts...), configs[provider]...)
+		providerResults, err := ValidateDetailed(fs, provider, providerOpts...)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		results[provider] = providerResults
+	}
+
+	return results, nil
+}
+
+// ValidateProvidersConcurrent runs ValidateDetailed for each of the given providers in
+// parallel and merges their results, which are tagged with their provider, in the order the
+// providers are given. The filesystem and logger must be safe for concurrent use. It returns
+// the error of the first provider which could not be validated.
+func ValidateProvidersConcurrent(fs filesystem.Filesystem, providers []string, opts ...Option) ([]ValidationResult, error) {
+	// Every provider writes into its own slot so that the merged results and the returned
+	// error are independent of scheduling.
+	results := make([][]ValidationResult, len(providers))
+	errs := make([]error, len(providers))
+	{
+		var wg sync.WaitGroup
+		for i := range providers {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				results[i], errs[i] = ValidateDetailed(fs, providers[i], opts...)
+			}(i)
+		}
+		wg.Wait()
+	}
+
+	var merged []ValidationResult
+	for i := range providers {
+		if errs[i] != nil {
+			return nil, microerror.Mask(errs[i])
+		}
+		merged = append(merged, results[i]...)
+	}
+
+	return merged, nil
+}
+
+// ValidateAgainstIndex cross-checks the active and archived releases of the given provider
+// against a separately generated helm-style release index like
+//
+//	entries:
+//	  aws:
+//	  - version: v1.0.0
+//
+// Every release on disk must be listed in the index and every release in the index must
+// exist on disk. Versions are compared in normalized form, see key.NormalizeVersion. It
+// returns the first mismatch found.
+func ValidateAgainstIndex(fs filesystem.Filesystem, provider string, indexData []byte, opts ...Option) error {
+	r := newRun(fs, provider, opts...)
+	exists, err := r.exists(r.provider)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	if !exists {
+		return microerror.Maskf(providerNotFoundError, "provider directory %s does not exist", r.provider)
+	}
+
+	var index releaseIndexFile
+	err = yaml.UnmarshalStrict(indexData, &index)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	indexed := map[string]bool{}
+	for _, entry := range index.Entries[r.provider] {
+		indexed[key.NormalizeVersion(entry.Version)] = true
+	}
+
+	onDisk := map[string]bool{}
+	for _, archived := range []bool{false, true} {
+		releases, err := r.findAllReleases(archived)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		for _, release := range releases {
+			name := key.NormalizeVersion(release.Name)
+			onDisk[name] = true
+			if !indexed[name] {
+				return microerror.Mask(fmt.Errorf("%s release %s is missing from the release index", r.provider, release.Name))
+			}
+		}
+	}
+
+	for _, entry := range index.Entries[r.provider] {
+		if !onDisk[key.NormalizeVersion(entry.Version)] {
+			return microerror.Mask(fmt.Errorf("release index lists %s release %s which doesn't exist", r.provider, entry.Version))
+		}
+	}
+
+	return nil
+}
+
+// ValidateAgainstLock checks that the components of every active release of the given
+// provider exactly match the versions pinned in a lockfile like
+//
+//	components:
+//	  kubernetes: 1.18.9
+//
+// Components missing from the lockfile aren't checked. All deviations are reported in a
+// single error, one line per release and component.
+func ValidateAgainstLock(fs filesystem.Filesystem, provider string, lockData []byte, opts ...Option) error {
+	r := newRun(fs, provider, opts...)
+	exists, err := r.exists(r.provider)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	if !exists {
+		return microerror.Maskf(providerNotFoundError, "provider directory %s does not exist", r.provider)
+	}
+
+	var lock componentLockFile
+	err = yaml.UnmarshalStrict(lockData, &lock)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	releases, err := r.findReleases(false)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	var deviations []string
+	for _, release := range releases {
+		if release.Spec.State != v1alpha1.StateActive {
+			continue
+		}
+		for _, component := range release.Spec.Components {
+			locked, ok := lock.Components[component.Name]
+			if ok && component.Version != locked {
+				deviations = append(deviations, fmt.Sprintf("component %s in %s release %s is at version %s but locked to %s", component.Name, r.provider, release.Name, component.Version, locked))
+			}
+		}
+	}
+	if len(deviations) > 0 {
+		return microerror.Mask(fmt.Errorf("%s", strings.Join(deviations, "\n")))
+	}
+
+	return nil
+}
+
+// releaseValidators are the validators which only need a release manifest, see
+// ValidateReleaseBytes.
+var releaseValidators = []validator{
+	{name: "release-names", validate: validateReleaseNames},
+	{name: "crd", validate: validateReleasesAgainstCRD},
+	{name: "duplicate-keys", validate: validateDuplicateKeys},
+	{name: "versions", validate: validateVersions},
+	{name: "app-component-versions", validate: validateAppComponentVersions},
+	{name: "component-version-prefix", validate: validateComponentVersionPrefix},
+	{name: "name-casing", validate: validateNameCasing},
+	{name: "app-catalogs", validate: validateAppCatalogs},
+	{name: "required-apps", validate: validateRequiredApps},
+	{name: "minimum-versions", validate: validateMinimumVersions},
+	{name: "release-labels", validate: validateReleaseLabels},
+	{name: "date-format", validate: validateDateFormat},
+	{name: "spec-version", validate: validateSpecVersion},
+}
+
+// ValidateReleaseBytes runs the checks which only need the release manifest itself, like the
+// CRD schema and version checks, against a single release.yaml of the given provider, e.g.
+// read from stdin. It returns the first error found.
+func ValidateReleaseBytes(data []byte, provider string, opts ...Option) error {
+	var release v1alpha1.Release
+	err := yaml.Unmarshal(data, &release)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	if release.Name == "" || strings.ContainsAny(release.Name, "/\\") {
+		return microerror.Mask(fmt.Errorf("release manifest must have a valid name, got %q", release.Name))
+	}
+
+	fs := filesystem.NewMemory(map[string]string{
+		path.Join(provider, release.Name, key.ReleaseFilename): string(data),
+	})
+	r := newRun(fs, provider, opts...)
+	err = r.execute(releaseValidators, true)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+// ValidateProposed validates a release which isn't written yet against the existing releases
+// of the given provider, e.g. before automation creates its files. It runs the checks of
+// ValidateReleaseBytes, ensures that the release is unique and doesn't share its date with an
+// active release and checks it against the requests. It returns the first error found.
+func ValidateProposed(existing []v1alpha1.Release, proposed v1alpha1.Release, requests requests2.Requests, provider string, opts ...Option) error {
+	if proposed.Name == "" || strings.ContainsAny(proposed.Name, "/\\") {
+		return microerror.Mask(fmt.Errorf("proposed release must have a valid name, got %q", proposed.Name))
+	}
+
+	for _, release := range existing {
+		if key.NormalizeVersion(release.Name) == key.NormalizeVersion(proposed.Name) {
+			return microerror.Mask(fmt.Errorf("%s release %s already exists", provider, proposed.Name))
+		}
 	}
 
-	for _, v := range validations {
-		err := v(fs, provider)
+	files := map[string]string{}
+	releases := append(append([]v1alpha1.Release{}, existing...), proposed)
+	for _, release := range releases {
+		data, err := patch.MarshalRelease(release)
 		if err != nil {
 			return microerror.Mask(err)
 		}
+		files[path.Join(provider, release.Name, key.ReleaseFilename)] = string(data)
+	}
+
+	r := newRun(filesystem.NewMemory(files), provider, opts...)
+	r.release = proposed.Name
+	validators := append([]validator{}, releaseValidators...)
+	validators = append(validators, validator{name: "version-bundle", validate: validateVersionBundle})
+	err := r.execute(validators, true)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	// Check that the proposed release can be ordered among the active releases by its date.
+	if proposed.Spec.State == v1alpha1.StateActive && proposed.Spec.Date != nil {
+		for _, release := range existing {
+			if release.Spec.State != v1alpha1.StateActive || release.Spec.Date == nil {
+				continue
+			}
+			if release.Spec.Date.Equal(proposed.Spec.Date) {
+				return microerror.Mask(fmt.Errorf("proposed %s release %s shares the date %s with active release %s, their order is ambiguous", provider, proposed.Name, proposed.Spec.Date.UTC().Format(time.RFC3339), release.Name))
+			}
+		}
+	}
+
+	err = requests.Check(proposed)
+	if err != nil {
+		return microerror.Mask(err)
 	}
 
 	return nil
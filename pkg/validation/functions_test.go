@@ -0,0 +1,2824 @@
+package validation
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/giantswarm/apiextensions/v2/pkg/apis/release/v1alpha1"
+	"github.com/google/go-cmp/cmp"
+	"sigs.k8s.io/yaml"
+
+	"github.com/giantswarm/releaseclient/pkg/filesystem"
+	"github.com/giantswarm/releaseclient/pkg/key"
+	requests2 "github.com/giantswarm/releaseclient/pkg/requests"
+)
+
+// newTestFilesystem writes the given files into a temporary directory and returns a
+// filesystem rooted there. Keys are slash-separated paths relative to the root.
+func newTestFilesystem(t *testing.T, files map[string]string) filesystem.Filesystem {
+	root, err := ioutil.TempDir("", "releaseclient-validation")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = os.RemoveAll(root)
+	})
+
+	for path, content := range files {
+		fullPath := filepath.Join(root, filepath.FromSlash(path))
+		err = os.MkdirAll(filepath.Dir(fullPath), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = ioutil.WriteFile(fullPath, []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return filesystem.New(root)
+}
+
+// assertError fails the test unless err contains errorContains, or is nil when errorContains is empty.
+func assertError(t *testing.T, err error, errorContains string) {
+	t.Helper()
+	if errorContains == "" && err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if errorContains != "" && (err == nil || !strings.Contains(err.Error(), errorContains)) {
+		t.Fatalf("expected error containing %q, got %v", errorContains, err)
+	}
+}
+
+// validProviderFiles returns the files of a repository with a single valid aws provider.
+func validProviderFiles() map[string]string {
+	return map[string]string{
+		"README.md": `# Releases
+
+- [v1.0.0](https://github.com/giantswarm/releases/tree/master/aws/v1.0.0)
+- [v0.9.0](https://github.com/giantswarm/releases/tree/master/aws/archived/v0.9.0)
+`,
+		"aws/kustomization.yaml":                 kustomization("v1.0.0"),
+		"aws/requests.yaml":                      "releases: []\n",
+		"aws/v1.0.0/README.md":                   "# :zap: Giant Swarm Release v1.0.0 for AWS :zap:\n",
+		"aws/v1.0.0/kustomization.yaml":          kustomization("release.yaml"),
+		"aws/v1.0.0/release.yaml":                releaseManifest("v1.0.0", "active"),
+		"aws/archived/kustomization.yaml":        kustomization("v0.9.0"),
+		"aws/archived/v0.9.0/README.md":          "# :zap: Giant Swarm Release v0.9.0 for AWS :zap:\n",
+		"aws/archived/v0.9.0/kustomization.yaml": kustomization("release.yaml"),
+		"aws/archived/v0.9.0/release.yaml":       releaseManifest("v0.9.0", "deprecated"),
+	}
+}
+
+// releaseManifest returns a minimal release.yaml for the given release name and state.
+func releaseManifest(name string, state string) string {
+	return fmt.Sprintf(`apiVersion: release.giantswarm.io/v1alpha1
+kind: Release
+metadata:
+  name: %s
+spec:
+  apps:
+  - name: cert-exporter
+    version: 1.2.3
+  components:
+  - name: kubernetes
+    version: 1.18.9
+  date: "2020-09-01T12:00:00Z"
+  state: %s
+`, name, state)
+}
+
+// kustomization returns a kustomization.yaml listing the given resources.
+func kustomization(resources ...string) string {
+	content := "apiVersion: kustomize.config.k8s.io/v1beta1\nkind: Kustomization\nresources:\n"
+	for _, resource := range resources {
+		content += fmt.Sprintf("- %s\n", resource)
+	}
+	return content
+}
+
+func Test_Validation_validateKustomization(t *testing.T) {
+	testCases := []struct {
+		name          string
+		files         map[string]string
+		errorContains string
+	}{
+		{
+			name: "case 0: active and archived releases registered correctly",
+			files: map[string]string{
+				"aws/kustomization.yaml":                 kustomization("v1.1.0"),
+				"aws/v1.1.0/kustomization.yaml":          kustomization("release.yaml"),
+				"aws/v1.1.0/release.yaml":                releaseManifest("v1.1.0", "active"),
+				"aws/archived/kustomization.yaml":        kustomization("v1.0.0"),
+				"aws/archived/v1.0.0/kustomization.yaml": kustomization("release.yaml"),
+				"aws/archived/v1.0.0/release.yaml":       releaseManifest("v1.0.0", "deprecated"),
+			},
+		},
+		{
+			name: "case 1: archived release registered in active kustomization",
+			files: map[string]string{
+				"aws/kustomization.yaml":                 kustomization("v1.0.0", "v1.1.0"),
+				"aws/v1.1.0/kustomization.yaml":          kustomization("release.yaml"),
+				"aws/v1.1.0/release.yaml":                releaseManifest("v1.1.0", "active"),
+				"aws/archived/kustomization.yaml":        kustomization("v1.0.0"),
+				"aws/archived/v1.0.0/kustomization.yaml": kustomization("release.yaml"),
+				"aws/archived/v1.0.0/release.yaml":       releaseManifest("v1.0.0", "deprecated"),
+			},
+			errorContains: "archived release v1.0.0 must not be registered in aws/kustomization.yaml",
+		},
+		{
+			name: "case 2: archived release missing from archived kustomization",
+			files: map[string]string{
+				"aws/kustomization.yaml":                 kustomization("v1.1.0"),
+				"aws/v1.1.0/kustomization.yaml":          kustomization("release.yaml"),
+				"aws/v1.1.0/release.yaml":                releaseManifest("v1.1.0", "active"),
+				"aws/archived/kustomization.yaml":        kustomization(),
+				"aws/archived/v1.0.0/kustomization.yaml": kustomization("release.yaml"),
+				"aws/archived/v1.0.0/release.yaml":       releaseManifest("v1.0.0", "deprecated"),
+			},
+			errorContains: "archived release v1.0.0 not registered in aws/archived/kustomization.yaml",
+		},
+		{
+			name: "case 3: active release registered in archived kustomization",
+			files: map[string]string{
+				"aws/kustomization.yaml":                 kustomization("v1.1.0"),
+				"aws/v1.1.0/kustomization.yaml":          kustomization("release.yaml"),
+				"aws/v1.1.0/release.yaml":                releaseManifest("v1.1.0", "active"),
+				"aws/archived/kustomization.yaml":        kustomization("v1.0.0", "v1.1.0"),
+				"aws/archived/v1.0.0/kustomization.yaml": kustomization("release.yaml"),
+				"aws/archived/v1.0.0/release.yaml":       releaseManifest("v1.0.0", "deprecated"),
+			},
+			errorContains: "active release v1.1.0 must not be registered in aws/archived/kustomization.yaml",
+		},
+		{
+			name: "case 4: existing transformer",
+			files: map[string]string{
+				"aws/kustomization.yaml":        kustomization("v1.1.0") + "transformers:\n- transformer.yaml\n",
+				"aws/transformer.yaml":          "",
+				"aws/v1.1.0/kustomization.yaml": kustomization("release.yaml"),
+				"aws/v1.1.0/release.yaml":       releaseManifest("v1.1.0", "active"),
+			},
+		},
+		{
+			name: "case 5: missing transformer",
+			files: map[string]string{
+				"aws/kustomization.yaml":        kustomization("v1.1.0"),
+				"aws/v1.1.0/kustomization.yaml": kustomization("release.yaml") + "transformers:\n- transformer.yaml\n",
+				"aws/v1.1.0/release.yaml":       releaseManifest("v1.1.0", "active"),
+				"transformer.yaml":              "",
+			},
+			errorContains: "transformer transformer.yaml referenced in aws/v1.1.0/kustomization.yaml not found at aws/v1.1.0/transformer.yaml",
+		},
+		{
+			name: "case 6: resources written as relative paths",
+			files: map[string]string{
+				"aws/kustomization.yaml":                 kustomization("./v1.1.0", "v1.2.0/"),
+				"aws/v1.1.0/kustomization.yaml":          kustomization("release.yaml"),
+				"aws/v1.1.0/release.yaml":                releaseManifest("v1.1.0", "active"),
+				"aws/v1.2.0/kustomization.yaml":          kustomization("release.yaml"),
+				"aws/v1.2.0/release.yaml":                releaseManifest("v1.2.0", "active"),
+				"aws/archived/kustomization.yaml":        kustomization("./v1.0.0"),
+				"aws/archived/v1.0.0/kustomization.yaml": kustomization("release.yaml"),
+				"aws/archived/v1.0.0/release.yaml":       releaseManifest("v1.0.0", "deprecated"),
+			},
+		},
+		{
+			name: "case 7: relative resource not matching a release",
+			files: map[string]string{
+				"aws/kustomization.yaml":        kustomization("./v1.1.0", "../v1.2.0"),
+				"aws/v1.1.0/kustomization.yaml": kustomization("release.yaml"),
+				"aws/v1.1.0/release.yaml":       releaseManifest("v1.1.0", "active"),
+			},
+			errorContains: "release ../v1.2.0 registered in aws/kustomization.yaml resources but not found",
+		},
+		{
+			name: "case 8: several releases missing kustomization.yaml",
+			files: map[string]string{
+				"aws/kustomization.yaml":        kustomization("v1.1.0", "v1.2.0", "v1.3.0"),
+				"aws/v1.1.0/release.yaml":       releaseManifest("v1.1.0", "active"),
+				"aws/v1.2.0/kustomization.yaml": kustomization("release.yaml"),
+				"aws/v1.2.0/release.yaml":       releaseManifest("v1.2.0", "active"),
+				"aws/v1.3.0/release.yaml":       releaseManifest("v1.3.0", "active"),
+			},
+			errorContains: "missing file for aws release v1.1.0: ",
+		},
+		{
+			name: "case 9: transformer relative to the release directory",
+			files: map[string]string{
+				"aws/kustomization.yaml":        kustomization("v1.1.0"),
+				"aws/v1.1.0/kustomization.yaml": kustomization("release.yaml") + "transformers:\n- transformer.yaml\n- ../labels.yaml\n",
+				"aws/v1.1.0/release.yaml":       releaseManifest("v1.1.0", "active"),
+				"aws/v1.1.0/transformer.yaml":   "",
+				"aws/labels.yaml":               "",
+			},
+		},
+		{
+			name: "case 10: transformer outside of the provider directory",
+			files: map[string]string{
+				"aws/kustomization.yaml":        kustomization("v1.1.0") + "transformers:\n- ../transformer.yaml\n",
+				"aws/v1.1.0/kustomization.yaml": kustomization("release.yaml"),
+				"aws/v1.1.0/release.yaml":       releaseManifest("v1.1.0", "active"),
+				"transformer.yaml":              "",
+			},
+			errorContains: "transformer ../transformer.yaml referenced in aws/kustomization.yaml resolves to transformer.yaml outside of provider directory aws",
+		},
+		{
+			name: "case 11: duplicated resource",
+			files: map[string]string{
+				"aws/kustomization.yaml":        kustomization("v1.1.0", "v1.2.0", "./v1.1.0"),
+				"aws/v1.1.0/kustomization.yaml": kustomization("release.yaml"),
+				"aws/v1.1.0/release.yaml":       releaseManifest("v1.1.0", "active"),
+				"aws/v1.2.0/kustomization.yaml": kustomization("release.yaml"),
+				"aws/v1.2.0/release.yaml":       releaseManifest("v1.2.0", "active"),
+			},
+			errorContains: "resource ./v1.1.0 listed more than once in aws/kustomization.yaml",
+		},
+		{
+			name: "case 12: malformed release kustomization.yaml",
+			files: map[string]string{
+				"aws/kustomization.yaml":        kustomization("v1.1.0"),
+				"aws/v1.1.0/kustomization.yaml": kustomization("release.yaml") + "resourcez: []\n",
+				"aws/v1.1.0/release.yaml":       releaseManifest("v1.1.0", "active"),
+			},
+			errorContains: "kustomization.yaml for aws release v1.1.0 is invalid",
+		},
+		{
+			name: "case 13: missing kustomization.yaml reported before later problems",
+			files: map[string]string{
+				"aws/kustomization.yaml":        kustomization("v1.1.0", "v1.2.0"),
+				"aws/v1.1.0/kustomization.yaml": kustomization("release.yaml", "extra.yaml"),
+				"aws/v1.1.0/release.yaml":       releaseManifest("v1.1.0", "active"),
+				"aws/v1.2.0/release.yaml":       releaseManifest("v1.2.0", "active"),
+				"aws/v1.3.0/release.yaml":       releaseManifest("v1.3.0", "active"),
+			},
+			errorContains: "missing file for aws release v1.2.0: ",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			fs := newTestFilesystem(t, tc.files)
+
+			err := validateKustomization(newRun(fs, "aws"))
+			assertError(t, err, tc.errorContains)
+		})
+	}
+
+	// Every missing release kustomization.yaml is reported in a single error.
+	fs := newTestFilesystem(t, testCases[8].files)
+	err := validateKustomization(newRun(fs, "aws"))
+	if err == nil || !strings.Contains(err.Error(), "missing file for aws release v1.1.0") || !strings.Contains(err.Error(), "missing file for aws release v1.3.0") {
+		t.Errorf("expected both missing kustomization files to be reported, got %v", err)
+	}
+}
+
+func Test_Validation_WithChangedPaths(t *testing.T) {
+	files := map[string]string{
+		"aws/v1.0.0/README.md":    "# :zap: Giant Swarm Release v1.0.0 for AWS :zap:\n",
+		"aws/v1.0.0/release.yaml": releaseManifest("v1.0.0", "active"),
+		"aws/v1.1.0/README.md":    "# :zap: Giant Swarm Release for AWS :zap:\n",
+		"aws/v1.1.0/release.yaml": releaseManifest("v1.1.0", "active"),
+	}
+
+	testCases := []struct {
+		name          string
+		options       []Option
+		errorContains string
+	}{
+		{
+			name:          "case 0: all releases are validated without changed paths",
+			errorContains: "aws release v1.1.0",
+		},
+		{
+			name:    "case 1: unrelated release is skipped",
+			options: []Option{WithChangedPaths([]string{"aws/v1.0.0/README.md", "azure/v1.1.0/README.md"})},
+		},
+		{
+			name:          "case 2: touched release is validated",
+			options:       []Option{WithChangedPaths([]string{"./aws/v1.1.0/README.md"})},
+			errorContains: "aws release v1.1.0",
+		},
+		{
+			name:          "case 3: provider-level file selects all releases",
+			options:       []Option{WithChangedPaths([]string{"aws/requests.yaml"})},
+			errorContains: "aws release v1.1.0",
+		},
+		{
+			name:    "case 4: empty change set skips all releases",
+			options: []Option{WithChangedPaths(nil)},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			fs := newTestFilesystem(t, files)
+
+			err := validateReleaseNotes(newRun(fs, "aws", tc.options...))
+			assertError(t, err, tc.errorContains)
+		})
+	}
+}
+
+func Test_Validation_validateDuplicateKeys(t *testing.T) {
+	testCases := []struct {
+		name          string
+		manifest      string
+		errorContains string
+	}{
+		{
+			name:     "case 0: no duplicate keys",
+			manifest: releaseManifest("v1.0.0", "active"),
+		},
+		{
+			name: "case 1: duplicate nested key",
+			manifest: `metadata:
+  name: v1.0.0
+spec:
+  components:
+  - name: kubernetes
+    version: 1.18.9
+    version: 1.18.10
+`,
+			errorContains: "release manifest aws/v1.0.0/release.yaml must not contain duplicate keys",
+		},
+		{
+			name: "case 2: duplicate top-level key",
+			manifest: `metadata:
+  name: v1.0.0
+spec:
+  state: active
+spec:
+  state: deprecated
+`,
+			errorContains: "key \"spec\" already set in map",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			fs := newTestFilesystem(t, map[string]string{
+				"aws/v1.0.0/release.yaml": tc.manifest,
+			})
+
+			err := validateDuplicateKeys(newRun(fs, "aws"))
+			assertError(t, err, tc.errorContains)
+		})
+	}
+}
+
+func Test_Validation_validateAppCatalogs(t *testing.T) {
+	manifest := func(catalog string) string {
+		return fmt.Sprintf(`metadata:
+  name: v1.0.0
+spec:
+  apps:
+  - name: cert-exporter
+    version: 1.2.3
+  - name: coredns
+    catalog: %s
+    version: 1.1.0
+`, catalog)
+	}
+
+	testCases := []struct {
+		name          string
+		manifest      string
+		options       []Option
+		errorContains string
+	}{
+		{
+			name:     "case 0: default catalog",
+			manifest: manifest("default"),
+		},
+		{
+			name:          "case 1: unknown catalog",
+			manifest:      manifest("playground"),
+			errorContains: "app coredns in aws release v1.0.0 references unknown catalog playground, allowed are default, giantswarm",
+		},
+		{
+			name:     "case 2: configured catalog",
+			manifest: manifest("playground"),
+			options:  []Option{WithAllowedCatalogs("playground")},
+		},
+		{
+			name:          "case 3: default catalog no longer allowed",
+			manifest:      manifest("default"),
+			options:       []Option{WithAllowedCatalogs("playground")},
+			errorContains: "references unknown catalog default",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			fs := newTestFilesystem(t, map[string]string{
+				"aws/v1.0.0/release.yaml": tc.manifest,
+			})
+
+			err := validateAppCatalogs(newRun(fs, "aws", tc.options...))
+			assertError(t, err, tc.errorContains)
+		})
+	}
+}
+
+func Test_Validation_validateComponentVersionPrefix(t *testing.T) {
+	mixed := strings.Replace(releaseManifest("v1.0.0", "active"), "version: 1.18.9", "version: v1.18.9", 1)
+
+	testCases := []struct {
+		name          string
+		manifest      string
+		options       []Option
+		errorContains string
+	}{
+		{
+			name:     "case 0: mixed prefixes accepted by default",
+			manifest: mixed,
+		},
+		{
+			name:     "case 1: no prefixes when forbidden",
+			manifest: releaseManifest("v1.0.0", "active"),
+			options:  []Option{WithComponentVersionPrefix(VersionPrefixForbidden)},
+		},
+		{
+			name:          "case 2: mixed prefixes when forbidden",
+			manifest:      mixed,
+			options:       []Option{WithComponentVersionPrefix(VersionPrefixForbidden)},
+			errorContains: "component kubernetes in aws release v1.0.0 must write version v1.18.9 without a v prefix",
+		},
+		{
+			name:          "case 3: mixed prefixes when required",
+			manifest:      mixed,
+			options:       []Option{WithComponentVersionPrefix(VersionPrefixRequired)},
+			errorContains: "app cert-exporter in aws release v1.0.0 must write version 1.2.3 with a v prefix",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			fs := newTestFilesystem(t, map[string]string{
+				"aws/v1.0.0/release.yaml": tc.manifest,
+			})
+
+			err := validateComponentVersionPrefix(newRun(fs, "aws", tc.options...))
+			assertError(t, err, tc.errorContains)
+		})
+	}
+}
+
+func Test_Validation_validateRequiredApps(t *testing.T) {
+	testCases := []struct {
+		name          string
+		manifest      string
+		options       []Option
+		errorContains string
+	}{
+		{
+			name:     "case 0: no required apps",
+			manifest: releaseManifest("v1.0.0", "active"),
+		},
+		{
+			name:     "case 1: required app present",
+			manifest: releaseManifest("v1.0.0", "active"),
+			options:  []Option{WithRequiredApps("cert-exporter")},
+		},
+		{
+			name:          "case 2: required app missing",
+			manifest:      releaseManifest("v1.0.0", "active"),
+			options:       []Option{WithRequiredApps("cert-exporter", "net-exporter")},
+			errorContains: "active aws release v1.0.0 must contain app net-exporter",
+		},
+		{
+			name:     "case 3: deprecated release missing the required app",
+			manifest: releaseManifest("v1.0.0", "deprecated"),
+			options:  []Option{WithRequiredApps("net-exporter")},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			fs := newTestFilesystem(t, map[string]string{
+				"aws/v1.0.0/release.yaml": tc.manifest,
+			})
+
+			err := validateRequiredApps(newRun(fs, "aws", tc.options...))
+			assertError(t, err, tc.errorContains)
+		})
+	}
+}
+
+func Test_Validation_validateMinimumVersions(t *testing.T) {
+	testCases := []struct {
+		name          string
+		manifest      string
+		options       []Option
+		errorContains string
+	}{
+		{
+			name:     "case 0: no minimum versions",
+			manifest: releaseManifest("v1.0.0", "active"),
+		},
+		{
+			name:     "case 1: release at the minimum",
+			manifest: releaseManifest("v1.0.0", "active"),
+			options:  []Option{WithMinimumVersions(map[string]string{"kubernetes": "1.18.9"})},
+		},
+		{
+			name:          "case 2: kubernetes below the minimum",
+			manifest:      releaseManifest("v1.0.0", "active"),
+			options:       []Option{WithMinimumVersions(map[string]string{"kubernetes": "1.19.0"})},
+			errorContains: "active aws release v1.0.0 ships kubernetes 1.18.9 which is below the supported minimum 1.19.0",
+		},
+		{
+			name:          "case 3: app below the minimum",
+			manifest:      releaseManifest("v1.0.0", "active"),
+			options:       []Option{WithMinimumVersions(map[string]string{"cert-exporter": "v1.3.0"})},
+			errorContains: "ships cert-exporter 1.2.3 which is below the supported minimum v1.3.0",
+		},
+		{
+			name:     "case 4: deprecated release below the minimum",
+			manifest: releaseManifest("v1.0.0", "deprecated"),
+			options:  []Option{WithMinimumVersions(map[string]string{"kubernetes": "1.19.0"})},
+		},
+		{
+			name:          "case 5: invalid minimum",
+			manifest:      releaseManifest("v1.0.0", "active"),
+			options:       []Option{WithMinimumVersions(map[string]string{"kubernetes": "latest"})},
+			errorContains: "minimum version \"latest\" for kubernetes must be a valid semver version",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			fs := newTestFilesystem(t, map[string]string{
+				"aws/v1.0.0/release.yaml": tc.manifest,
+			})
+
+			err := validateMinimumVersions(newRun(fs, "aws", tc.options...))
+			assertError(t, err, tc.errorContains)
+		})
+	}
+}
+
+func Test_Validation_validateReleaseNames(t *testing.T) {
+	testCases := []struct {
+		name          string
+		release       string
+		options       []Option
+		errorContains string
+	}{
+		{
+			name:    "case 0: semver name",
+			release: "v1.0.0",
+		},
+		{
+			name:          "case 1: invalid name",
+			release:       "v1.0",
+			errorContains: "name of aws release v1.0 must be a valid semver version",
+		},
+		{
+			name:    "case 2: name matching the pattern",
+			release: "v12.1.0",
+			options: []Option{WithReleaseNamePattern(regexp.MustCompile(`^v1[0-9]\.`))},
+		},
+		{
+			name:          "case 3: name not matching the pattern",
+			release:       "v1.0.0",
+			options:       []Option{WithReleaseNamePattern(regexp.MustCompile(`^v1[0-9]\.`))},
+			errorContains: `name of aws release v1.0.0 must match ^v1[0-9]\.`,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			fs := newTestFilesystem(t, map[string]string{
+				"aws/" + tc.release + "/release.yaml": releaseManifest(tc.release, "active"),
+			})
+
+			err := validateReleaseNames(newRun(fs, "aws", tc.options...))
+			assertError(t, err, tc.errorContains)
+		})
+	}
+}
+
+func Test_Validation_validateReleaseLabels(t *testing.T) {
+	labeled := func(provider string) string {
+		return strings.Replace(releaseManifest("v1.0.0", "active"), "  name: v1.0.0\n", fmt.Sprintf("  name: v1.0.0\n  labels:\n    giantswarm.io/provider: %s\n", provider), 1)
+	}
+
+	testCases := []struct {
+		name          string
+		manifest      string
+		options       []Option
+		errorContains string
+	}{
+		{
+			name:     "case 0: no labels required",
+			manifest: releaseManifest("v1.0.0", "active"),
+		},
+		{
+			name:          "case 1: missing provider label",
+			manifest:      releaseManifest("v1.0.0", "active"),
+			options:       []Option{WithRequiredLabels(key.ProviderLabel)},
+			errorContains: "aws release v1.0.0 must have label giantswarm.io/provider",
+		},
+		{
+			name:     "case 2: matching provider label",
+			manifest: labeled("aws"),
+			options:  []Option{WithRequiredLabels(key.ProviderLabel)},
+		},
+		{
+			name:          "case 3: provider label naming another provider",
+			manifest:      labeled("azure"),
+			errorContains: "aws release v1.0.0 has label giantswarm.io/provider=azure which doesn't match its provider",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			fs := newTestFilesystem(t, map[string]string{
+				"aws/v1.0.0/release.yaml": tc.manifest,
+			})
+
+			err := validateReleaseLabels(newRun(fs, "aws", tc.options...))
+			assertError(t, err, tc.errorContains)
+		})
+	}
+}
+
+func Test_Validation_validateActiveReleaseCount(t *testing.T) {
+	fs := newTestFilesystem(t, map[string]string{
+		"aws/v1.0.0/release.yaml": releaseManifest("v1.0.0", "deprecated"),
+		"aws/v1.1.0/release.yaml": releaseManifest("v1.1.0", "active"),
+		"aws/v1.2.0/release.yaml": releaseManifest("v1.2.0", "active"),
+		"aws/v1.3.0/release.yaml": releaseManifest("v1.3.0", "active"),
+	})
+
+	testCases := []struct {
+		name     string
+		options  []Option
+		expected []ValidationResult
+	}{
+		{
+			name: "case 0: disabled by default",
+		},
+		{
+			name:    "case 1: within the maximum",
+			options: []Option{WithMaxActiveReleases(3)},
+		},
+		{
+			name:    "case 2: exceeding the maximum",
+			options: []Option{WithMaxActiveReleases(2)},
+			expected: []ValidationResult{
+				{
+					Message:   "aws has 3 active releases, more than the maximum of 2, consider archiving old ones",
+					Provider:  "aws",
+					Severity:  SeverityWarning,
+					Validator: "active-release-count",
+				},
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			r := newRun(fs, "aws", tc.options...)
+			r.validator = "active-release-count"
+			err := validateActiveReleaseCount(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(r.results, tc.expected); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func Test_Validation_validateActiveReleaseOrder(t *testing.T) {
+	fs := newTestFilesystem(t, map[string]string{
+		"aws/v1.0.0/release.yaml": releaseManifest("v1.0.0", "deprecated"),
+		"aws/v1.1.0/release.yaml": releaseManifest("v1.1.0", "active"),
+		"aws/v1.2.0/release.yaml": releaseManifest("v1.2.0", "active"),
+		"aws/v1.3.0/release.yaml": strings.Replace(releaseManifest("v1.3.0", "active"), "2020-09-01", "2020-10-01", 1),
+	})
+
+	r := newRun(fs, "aws")
+	r.validator = "active-release-order"
+	err := validateActiveReleaseOrder(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []ValidationResult{
+		{
+			Message:   "active aws releases v1.1.0 and v1.2.0 share the date 2020-09-01T12:00:00Z, their order is ambiguous",
+			Provider:  "aws",
+			Release:   "v1.2.0",
+			Severity:  SeverityWarning,
+			Validator: "active-release-order",
+		},
+	}
+	if diff := cmp.Diff(r.results, expected); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func Test_Validation_validateIdenticalReleases(t *testing.T) {
+	fs := newTestFilesystem(t, map[string]string{
+		"aws/v1.0.0/release.yaml": releaseManifest("v1.0.0", "deprecated"),
+		"aws/v1.1.0/release.yaml": releaseManifest("v1.1.0", "active"),
+		"aws/v1.2.0/release.yaml": releaseManifest("v1.2.0", "active"),
+		"aws/v1.3.0/release.yaml": strings.Replace(releaseManifest("v1.3.0", "active"), "1.18.9", "1.18.10", 1),
+	})
+
+	r := newRun(fs, "aws")
+	r.validator = "identical-releases"
+	err := validateIdenticalReleases(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []ValidationResult{
+		{
+			Message:   "active aws releases v1.1.0 and v1.2.0 ship identical component and app versions",
+			Provider:  "aws",
+			Release:   "v1.2.0",
+			Severity:  SeverityWarning,
+			Validator: "identical-releases",
+		},
+	}
+	if diff := cmp.Diff(r.results, expected); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func Test_Validation_validatePrereleaseStates(t *testing.T) {
+	fs := newTestFilesystem(t, map[string]string{
+		"aws/v1.2.0/release.yaml":        releaseManifest("v1.2.0", "active"),
+		"aws/v1.3.0-alpha/release.yaml":  releaseManifest("v1.3.0-alpha", "wip"),
+		"aws/v1.3.0-beta.1/release.yaml": releaseManifest("v1.3.0-beta.1", "active"),
+	})
+
+	r := newRun(fs, "aws")
+	r.validator = "prerelease-states"
+	err := validatePrereleaseStates(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []ValidationResult{
+		{
+			Message:   "aws release v1.3.0-beta.1 is a pre-release but in state active, expected wip",
+			Provider:  "aws",
+			Release:   "v1.3.0-beta.1",
+			Severity:  SeverityWarning,
+			Validator: "prerelease-states",
+		},
+	}
+	if diff := cmp.Diff(r.results, expected); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func Test_Validation_validateStateTransitions(t *testing.T) {
+	base := func(state v1alpha1.ReleaseState) []v1alpha1.Release {
+		release := v1alpha1.Release{}
+		release.Name = "v1.0.0"
+		release.Spec.State = state
+		return []v1alpha1.Release{release}
+	}
+
+	testCases := []struct {
+		name          string
+		state         string
+		options       []Option
+		errorContains string
+	}{
+		{
+			name:  "case 0: no base releases",
+			state: "active",
+		},
+		{
+			name:    "case 1: wip to active",
+			state:   "active",
+			options: []Option{WithBaseReleases(base(v1alpha1.StateWIP))},
+		},
+		{
+			name:    "case 2: active to deprecated",
+			state:   "deprecated",
+			options: []Option{WithBaseReleases(base(v1alpha1.StateActive))},
+		},
+		{
+			name:          "case 3: deprecated to active",
+			state:         "active",
+			options:       []Option{WithBaseReleases(base(v1alpha1.StateDeprecated))},
+			errorContains: "aws release v1.0.0 must not change state from deprecated to active",
+		},
+		{
+			name:          "case 4: active to wip",
+			state:         "wip",
+			options:       []Option{WithBaseReleases(base(v1alpha1.StateActive))},
+			errorContains: "must not change state from active to wip",
+		},
+		{
+			name:  "case 5: base release named without v prefix",
+			state: "active",
+			options: []Option{WithBaseReleases(func() []v1alpha1.Release {
+				releases := base(v1alpha1.StateDeprecated)
+				releases[0].Name = "1.0.0"
+				return releases
+			}())},
+			errorContains: "aws release v1.0.0 must not change state from deprecated to active",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			fs := newTestFilesystem(t, map[string]string{
+				"aws/v1.0.0/release.yaml": releaseManifest("v1.0.0", tc.state),
+			})
+
+			err := validateStateTransitions(newRun(fs, "aws", tc.options...))
+			assertError(t, err, tc.errorContains)
+		})
+	}
+}
+
+func Test_Validation_validateProviderName(t *testing.T) {
+	testCases := []struct {
+		name          string
+		provider      string
+		options       []Option
+		errorContains string
+	}{
+		{
+			name:     "case 0: known provider",
+			provider: "aws",
+		},
+		{
+			name:          "case 1: typo in provider name",
+			provider:      "awss",
+			errorContains: "provider directory awss is not a known provider, known are aws, azure, kvm",
+		},
+		{
+			name:     "case 2: configured provider",
+			provider: "openstack",
+			options:  []Option{WithKnownProviders("aws", "openstack")},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			fs := newTestFilesystem(t, map[string]string{
+				filepath.Join(tc.provider, key.RequestsFilename): "releases: []\n",
+			})
+
+			err := validateProviderName(newRun(fs, tc.provider, tc.options...))
+			assertError(t, err, tc.errorContains)
+		})
+	}
+}
+
+func Test_Validation_validateRetiredProviderRequests(t *testing.T) {
+	requests := `releases:
+- name: ">= 11.0.0"
+  requests:
+  - name: kubernetes
+    version: ">= 1.16.0"
+`
+
+	testCases := []struct {
+		name          string
+		provider      string
+		requests      string
+		options       []Option
+		errorContains string
+	}{
+		{
+			name:     "case 0: no allowed providers",
+			provider: "kvm",
+			requests: requests,
+		},
+		{
+			name:     "case 1: allowed provider",
+			provider: "aws",
+			requests: requests,
+			options:  []Option{WithAllowedProviders("aws", "azure")},
+		},
+		{
+			name:     "case 2: retired provider without requests",
+			provider: "kvm",
+			requests: "releases: []\n",
+			options:  []Option{WithAllowedProviders("aws", "azure")},
+		},
+		{
+			name:          "case 3: retired provider with requests",
+			provider:      "kvm",
+			requests:      requests,
+			options:       []Option{WithAllowedProviders("aws", "azure")},
+			errorContains: "requests.yaml of retired provider kvm requests versions for releases >= 11.0.0",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			fs := newTestFilesystem(t, map[string]string{
+				filepath.Join(tc.provider, key.RequestsFilename): tc.requests,
+			})
+
+			err := validateRetiredProviderRequests(newRun(fs, tc.provider, tc.options...))
+			assertError(t, err, tc.errorContains)
+		})
+	}
+}
+
+func Test_Validation_validateAppComponentVersions(t *testing.T) {
+	testCases := []struct {
+		name          string
+		manifest      string
+		errorContains string
+	}{
+		{
+			name: "case 0: matching component version",
+			manifest: `metadata:
+  name: v1.0.0
+spec:
+  apps:
+  - name: coredns
+    componentVersion: 1.6.5
+    version: 1.1.0
+  components:
+  - name: coredns
+    version: 1.6.5
+`,
+		},
+		{
+			name: "case 1: app without a corresponding component",
+			manifest: `metadata:
+  name: v1.0.0
+spec:
+  apps:
+  - name: coredns
+    componentVersion: 1.6.5
+    version: 1.1.0
+  components:
+  - name: kubernetes
+    version: 1.18.9
+`,
+		},
+		{
+			name: "case 2: mismatched component version",
+			manifest: `metadata:
+  name: v1.0.0
+spec:
+  apps:
+  - name: coredns
+    componentVersion: 1.6.4
+    version: 1.1.0
+  components:
+  - name: coredns
+    version: 1.6.5
+`,
+			errorContains: "app coredns in aws release v1.0.0 has component version 1.6.4 but component coredns is at version 1.6.5",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			fs := newTestFilesystem(t, map[string]string{
+				"aws/v1.0.0/release.yaml": tc.manifest,
+			})
+
+			err := validateAppComponentVersions(newRun(fs, "aws"))
+			assertError(t, err, tc.errorContains)
+		})
+	}
+}
+
+func Test_Validation_validateNameCasing(t *testing.T) {
+	testCases := []struct {
+		name          string
+		manifest      string
+		errorContains string
+	}{
+		{
+			name: "case 0: matching names",
+			manifest: `metadata:
+  name: v1.0.0
+spec:
+  apps:
+  - name: chart-operator
+    componentVersion: 2.3.0
+    version: 2.3.0
+  components:
+  - name: chart-operator
+    version: 2.3.0
+`,
+		},
+		{
+			name: "case 1: casing mismatch",
+			manifest: `metadata:
+  name: v1.0.0
+spec:
+  apps:
+  - name: Chart-Operator
+    componentVersion: 2.3.0
+    version: 2.3.0
+  components:
+  - name: chart-operator
+    version: 2.3.0
+`,
+			errorContains: "app Chart-Operator in aws release v1.0.0 differs only in casing from component chart-operator",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			fs := newTestFilesystem(t, map[string]string{
+				"aws/v1.0.0/release.yaml": tc.manifest,
+			})
+
+			err := validateNameCasing(newRun(fs, "aws"))
+			assertError(t, err, tc.errorContains)
+		})
+	}
+}
+
+type capturingLogger struct {
+	messages []string
+}
+
+func (l *capturingLogger) Debugf(format string, args ...interface{}) {
+	l.messages = append(l.messages, fmt.Sprintf(format, args...))
+}
+
+func Test_Validation_WithLogger(t *testing.T) {
+	fs := newTestFilesystem(t, validProviderFiles())
+	logger := &capturingLogger{}
+
+	err := Validate(fs, "aws", WithLogger(logger))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"reading file aws/requests.yaml",
+		"found 1 releases for provider aws (archived: false)",
+		"reading file aws/v1.0.0/README.md",
+		"validator release-notes passed for provider aws",
+		"reading file aws/archived/kustomization.yaml",
+		"validator kustomization passed for provider aws",
+	}
+	for _, e := range expected {
+		found := false
+		for _, message := range logger.messages {
+			if message == e {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("expected message %q to be logged, got %q", e, logger.messages)
+		}
+	}
+}
+
+type recordingReporter struct {
+	events []string
+}
+
+func (r *recordingReporter) ReleaseStarted(release string) {
+	r.events = append(r.events, "release started "+release)
+}
+
+func (r *recordingReporter) ReleaseFinished(release string, err error) {
+	r.events = append(r.events, fmt.Sprintf("release finished %s: %v", release, err))
+}
+
+func (r *recordingReporter) ValidatorStarted(validator string) {
+	r.events = append(r.events, "validator started "+validator)
+}
+
+func (r *recordingReporter) ValidatorFinished(validator string, err error) {
+	r.events = append(r.events, fmt.Sprintf("validator finished %s: %v", validator, err))
+}
+
+func Test_Validation_ValidateWithReporter(t *testing.T) {
+	fs := newTestFilesystem(t, validProviderFiles())
+	reporter := &recordingReporter{}
+
+	err := ValidateWithReporter(fs, "aws", reporter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"release started v1.0.0",
+		"validator started release-names",
+		"validator finished release-names: <nil>",
+		"validator started crd",
+		"validator finished crd: <nil>",
+		"validator started duplicate-keys",
+		"validator finished duplicate-keys: <nil>",
+		"validator started versions",
+		"validator finished versions: <nil>",
+		"validator started app-component-versions",
+		"validator finished app-component-versions: <nil>",
+		"validator started component-version-prefix",
+		"validator finished component-version-prefix: <nil>",
+		"validator started name-casing",
+		"validator finished name-casing: <nil>",
+		"validator started app-catalogs",
+		"validator finished app-catalogs: <nil>",
+		"validator started required-apps",
+		"validator finished required-apps: <nil>",
+		"validator started minimum-versions",
+		"validator finished minimum-versions: <nil>",
+		"validator started release-labels",
+		"validator finished release-labels: <nil>",
+		"validator started date-format",
+		"validator finished date-format: <nil>",
+		"validator started spec-version",
+		"validator finished spec-version: <nil>",
+		"release finished v1.0.0: <nil>",
+	}
+	for _, name := range []string{"provider-name", "release-directories", "release-directory-case", "requests", "requests-canonical", "requests-order", "redundant-exceptions", "retired-provider-requests", "release-notes", "release-notes-sections", "release-notes-dates", "release-notes-links", "readme", "future-dates", "sunset-dates", "predecessor-contents", "component-downgrades", "state-transitions", "prerelease-states", "active-release-count", "version-bundle", "active-release-order", "identical-releases", "kustomization", "kustomization-types", "annotation-prefixes", "repository-annotation"} {
+		expected = append(expected, "validator started "+name, "validator finished "+name+": <nil>")
+	}
+	if diff := cmp.Diff(reporter.events, expected); diff != "" {
+		t.Error(diff)
+	}
+
+	files := validProviderFiles()
+	files["aws/v1.0.0/release.yaml"] = strings.Replace(files["aws/v1.0.0/release.yaml"], "1.18.9", "1.18", 1)
+	fs = newTestFilesystem(t, files)
+	reporter = &recordingReporter{}
+
+	err = ValidateWithReporter(fs, "aws", reporter)
+	if err == nil {
+		t.Fatal("expected an error for an invalid component version")
+	}
+	last := reporter.events[len(reporter.events)-1]
+	if !strings.HasPrefix(last, "release finished v1.0.0: ") || strings.HasSuffix(last, "<nil>") {
+		t.Errorf("expected the release to finish with an error, got %q", last)
+	}
+}
+
+func Test_Validation_validatePredecessorContents(t *testing.T) {
+	fs := newTestFilesystem(t, map[string]string{
+		"aws/v1.2.0/release.yaml": `metadata:
+  name: v1.2.0
+spec:
+  apps:
+  - name: cert-exporter
+    version: 1.2.3
+  components:
+  - name: kubernetes
+    version: 1.18.9
+  - name: calico
+    version: 3.15.1
+`,
+		"aws/v1.3.0/release.yaml": `metadata:
+  name: v1.3.0
+spec:
+  components:
+  - name: kubernetes
+    version: 1.18.10
+`,
+		"aws/v1.10.0/release.yaml": `metadata:
+  name: v1.10.0
+spec:
+  components:
+  - name: kubernetes
+    version: 1.18.10
+`,
+	})
+
+	r := newRun(fs, "aws")
+	r.validator = "predecessor-contents"
+	err := validatePredecessorContents(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []ValidationResult{
+		{
+			Message:   "component calico is missing from aws release v1.3.0 but was shipped in v1.2.0",
+			Provider:  "aws",
+			Release:   "v1.3.0",
+			Severity:  SeverityWarning,
+			Validator: "predecessor-contents",
+		},
+		{
+			Message:   "app cert-exporter is missing from aws release v1.3.0 but was shipped in v1.2.0",
+			Provider:  "aws",
+			Release:   "v1.3.0",
+			Severity:  SeverityWarning,
+			Validator: "predecessor-contents",
+		},
+	}
+	if diff := cmp.Diff(r.results, expected); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func Test_Validation_validateComponentDowngrades(t *testing.T) {
+	fs := newTestFilesystem(t, map[string]string{
+		"aws/v1.0.0/release.yaml": `metadata:
+  name: v1.0.0
+spec:
+  components:
+  - name: kubernetes
+    version: 1.20.2
+  - name: calico
+    version: 3.15.1
+`,
+		"aws/v1.1.0/release.yaml": `metadata:
+  name: v1.1.0
+spec:
+  components:
+  - name: kubernetes
+    version: 1.19.8
+  - name: calico
+    version: 3.15.1
+`,
+	})
+
+	r := newRun(fs, "aws")
+	r.validator = "component-downgrades"
+	err := validateComponentDowngrades(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.results) != 0 {
+		t.Fatalf("expected no results without WithDowngradeWarnings, got %#v", r.results)
+	}
+
+	r = newRun(fs, "aws", WithDowngradeWarnings())
+	r.validator = "component-downgrades"
+	err = validateComponentDowngrades(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []ValidationResult{
+		{
+			Message:   "component kubernetes is downgraded from 1.20.2 in v1.0.0 to 1.19.8 in aws release v1.1.0",
+			Provider:  "aws",
+			Release:   "v1.1.0",
+			Severity:  SeverityWarning,
+			Validator: "component-downgrades",
+		},
+	}
+	if diff := cmp.Diff(r.results, expected); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func Test_Validation_ValidateDetailed(t *testing.T) {
+	files := validProviderFiles()
+	files["aws/v1.0.0/README.md"] = "# Release notes\n"
+
+	fs := newTestFilesystem(t, files)
+
+	results, err := ValidateDetailed(fs, "aws")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %#v", results)
+	}
+	if results[0].Validator != "release-notes" || results[0].Severity != SeverityError {
+		t.Errorf("unexpected result %#v", results[0])
+	}
+
+	err = Validate(fs, "aws")
+	if err == nil {
+		t.Error("expected Validate to fail")
+	}
+}
+
+func Test_Validation_ValidateDetailed_UnloadableReleases(t *testing.T) {
+	testCases := []struct {
+		name            string
+		files           map[string]string
+		expectedMessage string
+	}{
+		{
+			name: "case 0: release directory without release.yaml",
+			files: map[string]string{
+				"aws/v1.4.0/README.md": "# :zap: Giant Swarm Release v1.4.0 for AWS :zap:\n",
+			},
+			expectedMessage: "release directory aws/v1.4.0 is missing release.yaml",
+		},
+		{
+			name: "case 1: release manifest with several documents",
+			files: map[string]string{
+				"aws/v1.0.0/release.yaml": releaseManifest("v1.0.0", "active") + "---\n" + releaseManifest("v1.0.1", "active"),
+			},
+			expectedMessage: "contains 2 YAML documents, expected exactly one release",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			files := validProviderFiles()
+			for name, content := range tc.files {
+				files[name] = content
+			}
+			fs := newTestFilesystem(t, files)
+
+			results, err := ValidateDetailed(fs, "aws")
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			var found bool
+			for _, result := range results {
+				if strings.Contains(result.Message, tc.expectedMessage) {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("expected a result containing %q, got %#v", tc.expectedMessage, results)
+			}
+		})
+	}
+}
+
+func Test_Validation_Validate_WithoutArchive(t *testing.T) {
+	files := map[string]string{}
+	for name, content := range validProviderFiles() {
+		if !strings.HasPrefix(name, "aws/archived/") {
+			files[name] = content
+		}
+	}
+	files["README.md"] = "# Releases\n\n- [v1.0.0](https://github.com/giantswarm/releases/tree/master/aws/v1.0.0)\n"
+	fs := newTestFilesystem(t, files)
+
+	err := Validate(fs, "aws")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+func Test_Validation_ValidateAllProviders(t *testing.T) {
+	files := map[string]string{
+		".github/workflows/README.md": "",
+	}
+	for name, content := range validProviderFiles() {
+		files[name] = content
+		if strings.HasPrefix(name, "aws/") {
+			files["azure/"+strings.TrimPrefix(name, "aws/")] = strings.Replace(content, "AWS", "Azure", 1)
+		}
+	}
+	files["README.md"] += `- [v1.0.0](https://github.com/giantswarm/releases/tree/master/azure/v1.0.0)
+- [v0.9.0](https://github.com/giantswarm/releases/tree/master/azure/archived/v0.9.0)
+`
+	files["aws/v1.0.0/README.md"] = "# Release notes\n"
+	files["azure/kustomization.yaml"] = kustomization()
+
+	results, err := ValidateAllProviders(filesystem.NewMemory(files))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var providers []string
+	for provider := range results {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+	if diff := cmp.Diff(providers, []string{"aws", "azure"}); diff != "" {
+		t.Fatal(diff)
+	}
+
+	if len(results["aws"]) != 1 || results["aws"][0].Validator != "release-notes" {
+		t.Errorf("unexpected aws results %#v", results["aws"])
+	}
+	if len(results["azure"]) != 1 || results["azure"][0].Validator != "kustomization" || results["azure"][0].Provider != "azure" {
+		t.Errorf("unexpected azure results %#v", results["azure"])
+	}
+}
+
+func Test_Validation_ValidateProviders(t *testing.T) {
+	files := map[string]string{}
+	for name, content := range validProviderFiles() {
+		files[name] = content
+		if strings.HasPrefix(name, "aws/") {
+			files["azure/"+strings.TrimPrefix(name, "aws/")] = strings.Replace(content, "AWS", "Azure", 1)
+		}
+	}
+	files["README.md"] += `- [v1.0.0](https://github.com/giantswarm/releases/tree/master/azure/v1.0.0)
+- [v0.9.0](https://github.com/giantswarm/releases/tree/master/azure/archived/v0.9.0)
+`
+	files["azure/kustomization.yaml"] = kustomization()
+	fs := filesystem.NewMemory(files)
+
+	configs := map[string][]Option{
+		"aws":   {WithRequiredApps("net-exporter")},
+		"azure": {WithSkippedValidators("kustomization")},
+	}
+	results, err := ValidateProviders(fs, configs, WithRequiredApps("cert-exporter"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results["aws"]) != 1 || results["aws"][0].Validator != "required-apps" || !strings.Contains(results["aws"][0].Message, "must contain app net-exporter") {
+		t.Errorf("unexpected aws results %#v", results["aws"])
+	}
+	if len(results["azure"]) != 0 {
+		t.Errorf("unexpected azure results %#v", results["azure"])
+	}
+
+	_, err = ValidateProviders(fs, map[string][]Option{"kvm": nil})
+	if !IsProviderNotFound(err) {
+		t.Errorf("expected provider not found error, got %v", err)
+	}
+}
+
+func Test_Validation_ReleaseNamePattern(t *testing.T) {
+	fs := newTestFilesystem(t, validProviderFiles())
+
+	err := Validate(fs, "aws", WithReleaseNamePattern(regexp.MustCompile(`^v1\.`)))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	err = Validate(fs, "aws", WithReleaseNamePattern(regexp.MustCompile(`^v9\.`)))
+	assertError(t, err, `name of aws release v1.0.0 must match ^v9\.`)
+
+	results, err := ValidateProviders(fs, map[string][]Option{"aws": {WithReleaseNamePattern(regexp.MustCompile(`^v9\.`))}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results["aws"]) != 1 || results["aws"][0].Validator != "release-names" || results["aws"][0].Release != "v1.0.0" {
+		t.Errorf("unexpected aws results %#v", results["aws"])
+	}
+}
+
+func Test_Validation_ValidateProvidersConcurrent(t *testing.T) {
+	files := map[string]string{}
+	for name, content := range validProviderFiles() {
+		files[name] = content
+		if strings.HasPrefix(name, "aws/") {
+			files["azure/"+strings.TrimPrefix(name, "aws/")] = strings.Replace(content, "AWS", "Azure", 1)
+		}
+	}
+	files["README.md"] += `- [v1.0.0](https://github.com/giantswarm/releases/tree/master/azure/v1.0.0)
+- [v0.9.0](https://github.com/giantswarm/releases/tree/master/azure/archived/v0.9.0)
+`
+	files["aws/v1.0.0/README.md"] = "# Release notes\n"
+	files["azure/kustomization.yaml"] = kustomization()
+	fs := filesystem.NewCaching(newTestFilesystem(t, files))
+
+	results, err := ValidateProvidersConcurrent(fs, []string{"azure", "aws"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var found []string
+	for _, result := range results {
+		found = append(found, result.Provider+" "+result.Validator)
+	}
+	if diff := cmp.Diff(found, []string{"azure kustomization", "aws release-notes"}); diff != "" {
+		t.Error(diff)
+	}
+
+	_, err = ValidateProvidersConcurrent(fs, []string{"aws", "kvm"})
+	if !IsProviderNotFound(err) {
+		t.Errorf("expected provider not found error, got %v", err)
+	}
+}
+
+func Test_Validation_ValidateReleaseBytes(t *testing.T) {
+	testCases := []struct {
+		name          string
+		manifest      string
+		errorContains string
+	}{
+		{
+			name:     "case 0: valid manifest",
+			manifest: releaseManifest("v1.0.0", "active"),
+		},
+		{
+			name:          "case 1: invalid release name",
+			manifest:      releaseManifest("v1.0", "active"),
+			errorContains: "name of aws release v1.0 must be a valid semver version",
+		},
+		{
+			name:          "case 2: invalid component version",
+			manifest:      strings.Replace(releaseManifest("v1.0.0", "active"), "version: 1.18.9", "version: latest", 1),
+			errorContains: "aws release v1.0.0 is invalid against CRD version v1alpha1",
+		},
+		{
+			name:          "case 3: missing name",
+			manifest:      "spec:\n  state: active\n",
+			errorContains: "release manifest must have a valid name",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			err := ValidateReleaseBytes([]byte(tc.manifest), "aws")
+			assertError(t, err, tc.errorContains)
+		})
+	}
+}
+
+func Test_Validation_ValidateProposed(t *testing.T) {
+	var requests requests2.Requests
+	err := requests.Load([]byte(`releases:
+- name: ">= 1.0.0"
+  requests:
+  - name: kubernetes
+    version: ">= 1.18.0"
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// proposal returns an active release manifest which differs from v1.0.0 by its app version.
+	proposal := func(name string, date string, kubernetes string) string {
+		manifest := strings.Replace(releaseManifest(name, "active"), "version: 1.2.3", "version: 1.2.4", 1)
+		manifest = strings.Replace(manifest, "2020-09-01T12:00:00Z", date, 1)
+		return strings.Replace(manifest, "version: 1.18.9", "version: "+kubernetes, 1)
+	}
+
+	testCases := []struct {
+		name          string
+		manifest      string
+		errorContains string
+	}{
+		{
+			name:     "case 0: valid proposal",
+			manifest: proposal("v1.1.0", "2020-10-01T12:00:00Z", "1.18.9"),
+		},
+		{
+			name:          "case 1: duplicate version",
+			manifest:      proposal("1.0.0", "2020-10-01T12:00:00Z", "1.18.9"),
+			errorContains: "aws release 1.0.0 already exists",
+		},
+		{
+			name:          "case 2: invalid component version",
+			manifest:      proposal("v1.1.0", "2020-10-01T12:00:00Z", "latest"),
+			errorContains: "aws release v1.1.0 is invalid against CRD version v1alpha1",
+		},
+		{
+			name:          "case 3: date shared with active release",
+			manifest:      proposal("v1.1.0", "2020-09-01T12:00:00Z", "1.18.9"),
+			errorContains: "proposed aws release v1.1.0 shares the date 2020-09-01T12:00:00Z with active release v1.0.0",
+		},
+		{
+			name:          "case 4: unsatisfied request",
+			manifest:      proposal("v1.1.0", "2020-10-01T12:00:00Z", "1.17.3"),
+			errorContains: "Release v1.1.0 does not meet the requested version requirements",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			var existing, proposed v1alpha1.Release
+			err := yaml.Unmarshal([]byte(releaseManifest("v1.0.0", "active")), &existing)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = yaml.Unmarshal([]byte(tc.manifest), &proposed)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = ValidateProposed([]v1alpha1.Release{existing}, proposed, requests, "aws")
+			assertError(t, err, tc.errorContains)
+		})
+	}
+}
+
+func Test_Validation_ValidateAgainstIndex(t *testing.T) {
+	testCases := []struct {
+		name          string
+		index         string
+		errorContains string
+	}{
+		{
+			name: "case 0: index matching the repository",
+			index: `entries:
+  aws:
+  - version: v1.0.0
+  - version: 0.9.0
+  azure:
+  - version: v2.0.0
+`,
+		},
+		{
+			name: "case 1: index missing a release",
+			index: `entries:
+  aws:
+  - version: v1.0.0
+`,
+			errorContains: "aws release v0.9.0 is missing from the release index",
+		},
+		{
+			name: "case 2: index listing an unknown release",
+			index: `entries:
+  aws:
+  - version: v1.0.0
+  - version: v0.9.0
+  - version: v1.1.0
+`,
+			errorContains: "release index lists aws release v1.1.0 which doesn't exist",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			fs := newTestFilesystem(t, validProviderFiles())
+
+			err := ValidateAgainstIndex(fs, "aws", []byte(tc.index))
+			assertError(t, err, tc.errorContains)
+		})
+	}
+}
+
+func Test_Validation_ValidateAgainstLock(t *testing.T) {
+	testCases := []struct {
+		name          string
+		lock          string
+		errorContains string
+	}{
+		{
+			name: "case 0: release matching the lock",
+			lock: `components:
+  kubernetes: 1.18.9
+`,
+		},
+		{
+			name: "case 1: component not locked",
+			lock: `components:
+  calico: 3.15.1
+`,
+		},
+		{
+			name: "case 2: release drifting from the lock",
+			lock: `components:
+  kubernetes: 1.18.10
+`,
+			errorContains: "component kubernetes in aws release v1.0.0 is at version 1.18.9 but locked to 1.18.10",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			fs := newTestFilesystem(t, validProviderFiles())
+
+			err := ValidateAgainstLock(fs, "aws", []byte(tc.lock))
+			assertError(t, err, tc.errorContains)
+		})
+	}
+}
+
+func Test_Validation_validateReadme(t *testing.T) {
+	testCases := []struct {
+		name          string
+		readme        string
+		options       []Option
+		errorContains string
+	}{
+		{
+			name: "case 0: default repository",
+			readme: `- [v1.0.0](https://github.com/giantswarm/releases/tree/master/aws/v1.0.0)
+- [v0.9.0](https://github.com/giantswarm/releases/tree/master/aws/archived/v0.9.0)
+`,
+		},
+		{
+			name: "case 1: active link to a different repository",
+			readme: `- [v1.0.0](https://github.com/giantswarm/releaseclient/tree/master/aws/v1.0.0)
+- [v0.9.0](https://github.com/giantswarm/releases/tree/master/aws/archived/v0.9.0)
+`,
+			errorContains: "expected link in README.md to aws release v1.0.0",
+		},
+		{
+			name: "case 2: configured repository",
+			readme: `- [v1.0.0](https://github.com/example/releases/tree/master/aws/v1.0.0)
+- [v0.9.0](https://github.com/example/releases/tree/master/aws/archived/v0.9.0)
+`,
+			options: []Option{WithRepository("https://github.com/example/releases/")},
+		},
+		{
+			name: "case 3: archived link to the default repository with a configured repository",
+			readme: `- [v1.0.0](https://github.com/example/releases/tree/master/aws/v1.0.0)
+- [v0.9.0](https://github.com/giantswarm/releases/tree/master/aws/archived/v0.9.0)
+`,
+			options:       []Option{WithRepository("https://github.com/example/releases")},
+			errorContains: "expected link in README.md to archived aws release v0.9.0",
+		},
+		{
+			name: "case 4: alternate archived layout",
+			readme: `- [v1.0.0](https://github.com/giantswarm/releases/tree/master/aws/v1.0.0)
+- [v0.9.0](https://github.com/giantswarm/releases/tree/master/archived/aws/v0.9.0)
+`,
+			options: []Option{WithArchivedLinkTemplate("{repository}/tree/master/archived/{provider}/{release}")},
+		},
+		{
+			name: "case 5: default archived layout with alternate template",
+			readme: `- [v1.0.0](https://github.com/giantswarm/releases/tree/master/aws/v1.0.0)
+- [v0.9.0](https://github.com/giantswarm/releases/tree/master/aws/archived/v0.9.0)
+`,
+			options:       []Option{WithArchivedLinkTemplate("{repository}/tree/master/archived/{provider}/{release}")},
+			errorContains: "expected link in README.md to archived aws release v0.9.0",
+		},
+		{
+			name: "case 6: duplicated active release link",
+			readme: `- [v1.0.0](https://github.com/giantswarm/releases/tree/master/aws/v1.0.0)
+- [v0.9.0](https://github.com/giantswarm/releases/tree/master/aws/archived/v0.9.0)
+- [v1.0.0](https://github.com/giantswarm/releases/tree/master/aws/v1.0.0)
+`,
+			errorContains: "expected a single link in README.md to aws release v1.0.0, found duplicates",
+		},
+		{
+			name: "case 7: link to a release sharing the version prefix",
+			readme: `- [v1.0.0](https://github.com/giantswarm/releases/tree/master/aws/v1.0.0)
+- [v1.0.01](https://github.com/giantswarm/releases/tree/master/aws/v1.0.01)
+- [v0.9.0](https://github.com/giantswarm/releases/tree/master/aws/archived/v0.9.0)
+`,
+		},
+		{
+			name: "case 8: links at the end of a sentence",
+			readme: `The latest release is https://github.com/giantswarm/releases/tree/master/aws/v1.0.0.
+It replaces https://github.com/giantswarm/releases/tree/master/aws/archived/v0.9.0.`,
+		},
+		{
+			name: "case 9: duplicated link at the end of a sentence",
+			readme: `- [v1.0.0](https://github.com/giantswarm/releases/tree/master/aws/v1.0.0)
+- [v0.9.0](https://github.com/giantswarm/releases/tree/master/aws/archived/v0.9.0)
+
+See https://github.com/giantswarm/releases/tree/master/aws/v1.0.0.
+`,
+			errorContains: "expected a single link in README.md to aws release v1.0.0, found duplicates",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			files := validProviderFiles()
+			files["README.md"] = tc.readme
+			fs := newTestFilesystem(t, files)
+
+			err := validateReadme(newRun(fs, "aws", tc.options...))
+			assertError(t, err, tc.errorContains)
+		})
+	}
+}
+
+func Test_Validation_WithStates(t *testing.T) {
+	files := validProviderFiles()
+	files["aws/v1.1.0/release.yaml"] = releaseManifest("v1.1.0", "wip")
+	fs := newTestFilesystem(t, files)
+
+	err := validateReadme(newRun(fs, "aws"))
+	assertError(t, err, "expected link in README.md to aws release v1.1.0")
+
+	err = validateReadme(newRun(fs, "aws", WithStates(v1alpha1.StateActive, v1alpha1.StateDeprecated)))
+	assertError(t, err, "")
+
+	releases, err := newRun(fs, "aws", WithStates(v1alpha1.StateWIP)).findReleases(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(releases) != 1 || releases[0].Name != "v1.1.0" {
+		t.Errorf("expected only the WIP release v1.1.0, got %#v", releases)
+	}
+}
+
+func Test_Validation_releaseLinks(t *testing.T) {
+	active := releaseLink(key.RepositoryURL, "aws", "v1.0.0")
+	archived := archivedReleaseLink(defaultArchivedLinkTemplate, key.RepositoryURL, "aws", "v0.9.0")
+
+	prefix := key.RepositoryURL + "/tree/master/aws/"
+	if !strings.HasPrefix(active, prefix) || !strings.HasPrefix(archived, prefix) {
+		t.Errorf("expected %s and %s to share the repository base %s", active, archived, prefix)
+	}
+}
+
+func Test_Validation_validateReleaseNotes(t *testing.T) {
+	testCases := []struct {
+		name          string
+		firstLine     string
+		options       []Option
+		errorContains string
+	}{
+		{
+			name:      "case 0: exact version",
+			firstLine: "# :zap: Giant Swarm Release v1.2.1 for AWS :zap:",
+		},
+		{
+			name:      "case 1: version without prefix",
+			firstLine: "# :zap: Giant Swarm Release 1.2.1 for AWS :zap:",
+		},
+		{
+			name:          "case 2: longer version containing the release version",
+			firstLine:     "# :zap: Giant Swarm Release v1.2.10 for AWS :zap:",
+			errorContains: "expected release notes for aws release v1.2.1 to contain the release version on the first line, found v1.2.10",
+		},
+		{
+			name:          "case 3: no version",
+			firstLine:     "# :zap: Giant Swarm Release for AWS :zap:",
+			errorContains: "expected release notes for aws release v1.2.1 to contain the release version on the first line",
+		},
+		{
+			name:      "case 4: required prefix",
+			firstLine: "# :zap: Giant Swarm Release v1.2.1 for AWS :zap:",
+			options:   []Option{WithVersionPrefix(VersionPrefixRequired)},
+		},
+		{
+			name:          "case 5: missing required prefix",
+			firstLine:     "# :zap: Giant Swarm Release 1.2.1 for AWS :zap:",
+			options:       []Option{WithVersionPrefix(VersionPrefixRequired)},
+			errorContains: "expected release notes for aws release v1.2.1 to write version 1.2.1 with a v prefix on the first line",
+		},
+		{
+			name:      "case 6: forbidden prefix absent",
+			firstLine: "# :zap: Giant Swarm Release 1.2.1 for AWS :zap:",
+			options:   []Option{WithVersionPrefix(VersionPrefixForbidden)},
+		},
+		{
+			name:          "case 7: forbidden prefix present",
+			firstLine:     "# :zap: Giant Swarm Release v1.2.1 for AWS :zap:",
+			options:       []Option{WithVersionPrefix(VersionPrefixForbidden)},
+			errorContains: "expected release notes for aws release v1.2.1 to write version v1.2.1 without a v prefix on the first line",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			fs := newTestFilesystem(t, map[string]string{
+				"aws/v1.2.1/README.md":    tc.firstLine + "\n\nSome notes.\n",
+				"aws/v1.2.1/release.yaml": releaseManifest("v1.2.1", "active"),
+			})
+
+			err := validateReleaseNotes(newRun(fs, "aws", tc.options...))
+			assertError(t, err, tc.errorContains)
+		})
+	}
+}
+
+func Test_Validation_validateReleaseNotesLinks(t *testing.T) {
+	testCases := []struct {
+		name          string
+		notes         string
+		errorContains string
+	}{
+		{
+			name:  "case 0: link to the release itself",
+			notes: "See [the manifest](https://github.com/giantswarm/releases/tree/master/aws/v1.2.1/release.yaml).\n",
+		},
+		{
+			name:  "case 1: link to the provider and unrelated links",
+			notes: "See [aws](https://github.com/giantswarm/releases/tree/master/aws) and [docs](https://docs.giantswarm.io/azure/v1.0.0).\n",
+		},
+		{
+			name:          "case 2: link to another provider",
+			notes:         "See [the manifest](https://github.com/giantswarm/releases/tree/master/azure/v1.2.1/release.yaml).\n",
+			errorContains: "release notes for aws release v1.2.1 link to provider azure: https://github.com/giantswarm/releases/tree/master/azure/v1.2.1/release.yaml",
+		},
+		{
+			name:          "case 3: link to another release",
+			notes:         "See [the manifest](https://github.com/giantswarm/releases/tree/master/aws/v1.2.0).\n",
+			errorContains: "release notes for aws release v1.2.1 link to release v1.2.0",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			fs := newTestFilesystem(t, map[string]string{
+				"aws/v1.2.1/README.md":    "# :zap: Giant Swarm Release v1.2.1 for AWS :zap:\n\n" + tc.notes,
+				"aws/v1.2.1/release.yaml": releaseManifest("v1.2.1", "active"),
+			})
+
+			err := validateReleaseNotesLinks(newRun(fs, "aws"))
+			assertError(t, err, tc.errorContains)
+		})
+	}
+}
+
+func Test_Validation_validateVersions(t *testing.T) {
+	testCases := []struct {
+		name          string
+		spec          string
+		errorContains string
+	}{
+		{
+			name: "case 0: valid versions",
+			spec: `  apps:
+  - name: coredns
+    componentVersion: 1.6.5
+    version: v1.1.0
+  components:
+  - name: kubernetes
+    version: 1.18.9-beta.1
+`,
+		},
+		{
+			name: "case 1: partial component version",
+			spec: `  components:
+  - name: kubernetes
+    version: v1.2
+`,
+			errorContains: "component kubernetes in aws release v1.0.0 has invalid version \"v1.2\"",
+		},
+		{
+			name: "case 2: non-semver app version",
+			spec: `  apps:
+  - name: coredns
+    version: latest
+`,
+			errorContains: "app coredns in aws release v1.0.0 has invalid version \"latest\"",
+		},
+		{
+			name: "case 3: invalid app component version",
+			spec: `  apps:
+  - name: coredns
+    componentVersion: "1.6"
+    version: 1.1.0
+`,
+			errorContains: "app coredns in aws release v1.0.0 has invalid component version \"1.6\"",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			fs := newTestFilesystem(t, map[string]string{
+				"aws/v1.0.0/release.yaml": "metadata:\n  name: v1.0.0\nspec:\n" + tc.spec,
+			})
+
+			err := validateVersions(newRun(fs, "aws"))
+			assertError(t, err, tc.errorContains)
+		})
+	}
+}
+
+func Test_Validation_validateReleasesAgainstCRD(t *testing.T) {
+	testCases := []struct {
+		name          string
+		state         string
+		archivedState string
+		options       []Option
+		errorContains string
+	}{
+		{
+			name:  "case 0: valid release against all versions",
+			state: "active",
+		},
+		{
+			name:    "case 1: valid release against a single version",
+			state:   "active",
+			options: []Option{WithCRDVersions("v1alpha1")},
+		},
+		{
+			name:          "case 2: invalid release against a single version",
+			state:         "released",
+			options:       []Option{WithCRDVersions("v1alpha1")},
+			errorContains: "aws release v1.0.0 is invalid against CRD version v1alpha1",
+		},
+		{
+			name:          "case 3: unknown version",
+			state:         "active",
+			options:       []Option{WithCRDVersions("v1alpha1", "v1beta1")},
+			errorContains: "CRD version v1beta1 selected for validation not found",
+		},
+		{
+			name:          "case 4: invalid archived release ignored by default",
+			state:         "active",
+			archivedState: "released",
+		},
+		{
+			name:          "case 5: invalid archived release",
+			state:         "active",
+			archivedState: "released",
+			options:       []Option{WithArchivedCRDValidation()},
+			errorContains: "archived aws release v0.9.0 is invalid against CRD version v1alpha1",
+		},
+		{
+			name:          "case 6: valid archived release",
+			state:         "active",
+			archivedState: "deprecated",
+			options:       []Option{WithArchivedCRDValidation()},
+		},
+		{
+			name:    "case 7: archived validation without archived directory",
+			state:   "active",
+			options: []Option{WithArchivedCRDValidation()},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			files := map[string]string{
+				"aws/v1.0.0/release.yaml": releaseManifest("v1.0.0", tc.state),
+			}
+			if tc.archivedState != "" {
+				files["aws/archived/v0.9.0/release.yaml"] = releaseManifest("v0.9.0", tc.archivedState)
+			}
+			fs := newTestFilesystem(t, files)
+
+			err := validateReleasesAgainstCRD(newRun(fs, "aws", tc.options...))
+			assertError(t, err, tc.errorContains)
+		})
+	}
+}
+
+func Test_Validation_validateRequests(t *testing.T) {
+	testCases := []struct {
+		name          string
+		requests      string
+		errorContains string
+	}{
+		{
+			name: "case 0: exception within the release pattern",
+			requests: `releases:
+- name: ">= 1.0.0"
+  requests:
+  - name: kubernetes
+    version: ">= 1.18.0"
+    except:
+    - releaseVersion: 1.0.1
+      reason: legacy
+`,
+		},
+		{
+			name: "case 1: exception outside the release pattern",
+			requests: `releases:
+- name: ">= 1.0.0"
+  requests:
+  - name: kubernetes
+    version: ">= 1.18.0"
+    except:
+    - releaseVersion: 0.9.0
+      reason: legacy
+`,
+			errorContains: "exception release version 0.9.0 for kubernetes must match releases >= 1.0.0, otherwise it excludes nothing",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			fs := newTestFilesystem(t, map[string]string{
+				"aws/requests.yaml":       tc.requests,
+				"aws/v1.0.0/release.yaml": releaseManifest("v1.0.0", "active"),
+			})
+
+			err := validateRequests(newRun(fs, "aws"))
+			assertError(t, err, tc.errorContains)
+		})
+	}
+}
+
+func Test_Validation_Validate_Requests(t *testing.T) {
+	testCases := []struct {
+		name          string
+		requests      string
+		errorContains string
+	}{
+		{
+			name: "case 0: satisfied request",
+			requests: `releases:
+- name: ">= 1.0.0"
+  requests:
+  - name: kubernetes
+    version: ">= 1.18.0"
+`,
+		},
+		{
+			name: "case 1: unsatisfied request",
+			requests: `releases:
+- name: ">= 1.0.0"
+  requests:
+  - name: kubernetes
+    version: ">= 1.19.0"
+`,
+			errorContains: "Release v1.0.0 does not meet the requested version requirements",
+		},
+		{
+			name: "case 2: unsatisfied baseline request",
+			requests: `baseline:
+- name: kubernetes
+  version: ">= 1.19.0"
+releases: []
+`,
+			errorContains: "Release v1.0.0 does not meet the requested version requirements",
+		},
+		{
+			name: "case 3: unsatisfied default request",
+			requests: `defaults:
+- name: kubernetes
+  version: ">= 1.19.0"
+releases:
+- name: ">= 2.0.0"
+  requests:
+  - name: kubernetes
+    version: ">= 1.20.0"
+`,
+			errorContains: "Release v1.0.0 does not meet the requested version requirements",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			files := validProviderFiles()
+			files["aws/requests.yaml"] = tc.requests
+			fs := newTestFilesystem(t, files)
+
+			err := Validate(fs, "aws")
+			assertError(t, err, tc.errorContains)
+		})
+	}
+}
+
+func Test_Validation_validateRequestsCanonical(t *testing.T) {
+	fs := newTestFilesystem(t, map[string]string{
+		"aws/requests.yaml": `baseline:
+- name: cert-exporter
+  version: ">= 1.2.0"
+releases:
+- name: ">=11.0.0"
+  requests:
+  - name: kubernetes
+    version: ">= 1.16.0"
+`,
+	})
+
+	r := newRun(fs, "aws")
+	r.validator = "requests-canonical"
+	err := validateRequestsCanonical(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []ValidationResult{
+		{
+			Message:   `version ">= 1.2.0" requested for cert-exporter in baseline in aws/requests.yaml should be written as ">=1.2.0"`,
+			Provider:  "aws",
+			Severity:  SeverityWarning,
+			Validator: "requests-canonical",
+		},
+		{
+			Message:   `version ">= 1.16.0" requested for kubernetes in releases ">=11.0.0" in aws/requests.yaml should be written as ">=1.16.0"`,
+			Provider:  "aws",
+			Severity:  SeverityWarning,
+			Validator: "requests-canonical",
+		},
+	}
+	if diff := cmp.Diff(r.results, expected); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func Test_Validation_validateRequestsOrder(t *testing.T) {
+	fs := newTestFilesystem(t, map[string]string{
+		"aws/requests.yaml": `releases:
+- name: ">=12.0.0"
+  requests:
+  - name: kubernetes
+    version: ">=1.17.0"
+- name: ">=11.0.0"
+  requests:
+  - name: kubernetes
+    version: ">=1.16.0"
+`,
+	})
+
+	r := newRun(fs, "aws")
+	r.validator = "requests-order"
+	err := validateRequestsOrder(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []ValidationResult{
+		{
+			Message:   `release pattern ">=11.0.0" in aws/requests.yaml is out of order, patterns should be sorted by the lowest release they match`,
+			Provider:  "aws",
+			Severity:  SeverityWarning,
+			Validator: "requests-order",
+		},
+	}
+	if diff := cmp.Diff(r.results, expected); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func Test_Validation_validateRedundantExceptions(t *testing.T) {
+	fs := newTestFilesystem(t, map[string]string{
+		"aws/requests.yaml": `releases:
+- name: ">=1.0.0"
+  requests:
+  - name: kubernetes
+    version: ">=1.18.0"
+    except:
+    - releaseVersion: v1.0.0
+      reason: legacy
+    - releaseVersion: v1.1.0
+      reason: legacy
+`,
+		"aws/v1.0.0/release.yaml": releaseManifest("v1.0.0", "active"),
+		"aws/v1.1.0/release.yaml": strings.Replace(releaseManifest("v1.1.0", "active"), "1.18.9", "1.17.4", 1),
+	})
+
+	r := newRun(fs, "aws")
+	r.validator = "redundant-exceptions"
+	err := validateRedundantExceptions(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []ValidationResult{
+		{
+			Message:   "exception for aws release v1.0.0 from request kubernetes >=1.18.0 in aws/requests.yaml is redundant, the release already satisfies it",
+			Provider:  "aws",
+			Release:   "v1.0.0",
+			Severity:  SeverityWarning,
+			Validator: "redundant-exceptions",
+		},
+	}
+	if diff := cmp.Diff(r.results, expected); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func Test_Validation_validateFutureDates(t *testing.T) {
+	now := time.Date(2020, 9, 1, 0, 0, 0, 0, time.UTC)
+	manifest := func(name string, state string, date string) string {
+		return strings.Replace(releaseManifest(name, state), "2020-09-01T12:00:00Z", date, 1)
+	}
+
+	testCases := []struct {
+		name     string
+		options  []Option
+		expected []string
+	}{
+		{
+			name:     "case 0: default threshold",
+			expected: []string{"v1.2.0"},
+		},
+		{
+			name:     "case 1: lower threshold",
+			options:  []Option{WithMaxFutureDays(7)},
+			expected: []string{"v1.1.0", "v1.2.0"},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			fs := newTestFilesystem(t, map[string]string{
+				"aws/v1.0.0/release.yaml": manifest("v1.0.0", "active", "2020-09-01T12:00:00Z"),
+				"aws/v1.1.0/release.yaml": manifest("v1.1.0", "active", "2020-09-20T12:00:00Z"),
+				"aws/v1.2.0/release.yaml": manifest("v1.2.0", "active", "2099-09-01T12:00:00Z"),
+				"aws/v1.3.0/release.yaml": manifest("v1.3.0", "wip", "2099-09-01T12:00:00Z"),
+			})
+
+			r := newRun(fs, "aws", tc.options...)
+			r.options.now = func() time.Time { return now }
+			err := validateFutureDates(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var warned []string
+			for _, result := range r.results {
+				warned = append(warned, result.Release)
+			}
+			if diff := cmp.Diff(warned, tc.expected); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func Test_Validation_validateSunsetDates(t *testing.T) {
+	now := time.Date(2020, 9, 1, 0, 0, 0, 0, time.UTC)
+	manifest := func(sunset string) string {
+		content := releaseManifest("v1.0.0", "deprecated")
+		if sunset != "" {
+			content = strings.Replace(content, "  name: v1.0.0\n", fmt.Sprintf("  name: v1.0.0\n  annotations:\n    release.giantswarm.io/sunset: %q\n", sunset), 1)
+		}
+		return content
+	}
+
+	testCases := []struct {
+		name          string
+		manifest      string
+		options       []Option
+		errorContains string
+	}{
+		{
+			name:     "case 0: disabled by default",
+			manifest: manifest(""),
+		},
+		{
+			name:          "case 1: deprecated release missing the sunset date",
+			manifest:      manifest(""),
+			options:       []Option{WithSunsetDates()},
+			errorContains: "deprecated aws release v1.0.0 must have a sunset date in spec.endOfLifeDate or annotation release.giantswarm.io/sunset",
+		},
+		{
+			name:     "case 2: sunset date in the future",
+			manifest: manifest("2020-12-31"),
+			options:  []Option{WithSunsetDates()},
+		},
+		{
+			name:          "case 3: sunset date in the past",
+			manifest:      manifest("2020-06-30"),
+			options:       []Option{WithSunsetDates()},
+			errorContains: "has sunset date 2020-06-30 which is not in the future",
+		},
+		{
+			name:          "case 4: invalid sunset date",
+			manifest:      manifest("end of 2020"),
+			options:       []Option{WithSunsetDates()},
+			errorContains: "has invalid release.giantswarm.io/sunset annotation",
+		},
+		{
+			name:     "case 5: end of life date in the future",
+			manifest: strings.Replace(manifest(""), "  state: deprecated\n", "  state: deprecated\n  endOfLifeDate: \"2020-12-31T00:00:00Z\"\n", 1),
+			options:  []Option{WithSunsetDates()},
+		},
+		{
+			name:     "case 6: active release without sunset date",
+			manifest: releaseManifest("v1.0.0", "active"),
+			options:  []Option{WithSunsetDates()},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			fs := newTestFilesystem(t, map[string]string{
+				"aws/v1.0.0/release.yaml": tc.manifest,
+			})
+
+			r := newRun(fs, "aws", tc.options...)
+			r.options.now = func() time.Time { return now }
+			err := validateSunsetDates(r)
+			assertError(t, err, tc.errorContains)
+		})
+	}
+}
+
+func Test_Validation_validateDateFormat(t *testing.T) {
+	manifest := func(date string) string {
+		return strings.Replace(releaseManifest("v1.0.0", "active"), `"2020-09-01T12:00:00Z"`, date, 1)
+	}
+
+	testCases := []struct {
+		name          string
+		manifest      string
+		errorContains string
+	}{
+		{
+			name:     "case 0: canonical UTC timestamp",
+			manifest: manifest(`"2020-09-01T12:00:00Z"`),
+		},
+		{
+			name:     "case 1: unquoted canonical UTC timestamp",
+			manifest: manifest("2020-09-01T12:00:00Z"),
+		},
+		{
+			name:          "case 2: timestamp with an offset",
+			manifest:      manifest(`"2020-09-01T14:00:00+02:00"`),
+			errorContains: `aws release v1.0.0 has date "2020-09-01T14:00:00+02:00", expected canonical UTC timestamp "2020-09-01T12:00:00Z"`,
+		},
+		{
+			name:          "case 3: timestamp with fractional seconds",
+			manifest:      manifest(`"2020-09-01T12:00:00.000Z"`),
+			errorContains: `expected canonical UTC timestamp "2020-09-01T12:00:00Z"`,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			fs := newTestFilesystem(t, map[string]string{
+				"aws/v1.0.0/release.yaml": tc.manifest,
+			})
+
+			err := validateDateFormat(newRun(fs, "aws"))
+			assertError(t, err, tc.errorContains)
+		})
+	}
+}
+
+func Test_Validation_validateSpecVersion(t *testing.T) {
+	manifest := func(version string) string {
+		return strings.Replace(releaseManifest("v1.0.0", "active"), "  state: active\n", "  state: active\n  version: "+version+"\n", 1)
+	}
+
+	testCases := []struct {
+		name          string
+		manifest      string
+		errorContains string
+	}{
+		{
+			name:     "case 0: no spec.version",
+			manifest: releaseManifest("v1.0.0", "active"),
+		},
+		{
+			name:     "case 1: spec.version without prefix",
+			manifest: manifest("1.0.0"),
+		},
+		{
+			name:     "case 2: spec.version with prefix",
+			manifest: manifest("v1.0.0"),
+		},
+		{
+			name:          "case 3: spec.version differing from the name",
+			manifest:      manifest("1.0.1"),
+			errorContains: "aws release v1.0.0 has spec.version 1.0.1 which does not match its name",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			fs := newTestFilesystem(t, map[string]string{
+				"aws/v1.0.0/release.yaml": tc.manifest,
+			})
+
+			err := validateSpecVersion(newRun(fs, "aws"))
+			assertError(t, err, tc.errorContains)
+		})
+	}
+}
+
+func Test_Validation_validateReleaseDirectoryCase(t *testing.T) {
+	testCases := []struct {
+		name          string
+		files         map[string]string
+		errorContains string
+	}{
+		{
+			name: "case 0: distinct release directories",
+			files: map[string]string{
+				"aws/v1.2.0/release.yaml":          releaseManifest("v1.2.0", "active"),
+				"aws/archived/v1.2.0/release.yaml": releaseManifest("v1.2.0", "deprecated"),
+			},
+		},
+		{
+			name: "case 1: directories differing only by case",
+			files: map[string]string{
+				"aws/V1.2.0/release.yaml": releaseManifest("v1.2.0", "active"),
+				"aws/v1.2.0/release.yaml": releaseManifest("v1.2.0", "active"),
+			},
+			errorContains: "release directories aws/V1.2.0 and aws/v1.2.0 differ only by case",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			fs := filesystem.NewMemory(tc.files)
+
+			err := validateReleaseDirectoryCase(newRun(fs, "aws"))
+			assertError(t, err, tc.errorContains)
+		})
+	}
+}
+
+func Test_Validation_validateReleaseDirectories(t *testing.T) {
+	testCases := []struct {
+		name          string
+		files         map[string]string
+		errorContains string
+	}{
+		{
+			name: "case 0: all release directories have manifests",
+			files: map[string]string{
+				"aws/v1.3.0/release.yaml":          releaseManifest("v1.3.0", "active"),
+				"aws/archived/v1.2.0/release.yaml": releaseManifest("v1.2.0", "deprecated"),
+				"aws/templates/README.md":          "",
+			},
+		},
+		{
+			name: "case 1: release directory with only release notes",
+			files: map[string]string{
+				"aws/v1.3.0/release.yaml": releaseManifest("v1.3.0", "active"),
+				"aws/v1.4.0/README.md":    "# :zap: Giant Swarm Release v1.4.0 for AWS :zap:\n",
+			},
+			errorContains: "release directory aws/v1.4.0 is missing release.yaml",
+		},
+		{
+			name: "case 2: archived release directory without manifest",
+			files: map[string]string{
+				"aws/v1.3.0/release.yaml":       releaseManifest("v1.3.0", "active"),
+				"aws/archived/v1.2.0/README.md": "",
+			},
+			errorContains: "release directory aws/archived/v1.2.0 is missing release.yaml",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			fs := newTestFilesystem(t, tc.files)
+
+			err := validateReleaseDirectories(newRun(fs, "aws"))
+			assertError(t, err, tc.errorContains)
+		})
+	}
+}
+
+func Test_Validation_validateKustomizationTypes(t *testing.T) {
+	testCases := []struct {
+		name          string
+		kustomization string
+		errorContains string
+	}{
+		{
+			name:          "case 0: valid kustomization",
+			kustomization: kustomization("v1.0.0"),
+		},
+		{
+			name:          "case 1: missing kind",
+			kustomization: "apiVersion: kustomize.config.k8s.io/v1beta1\nresources:\n- v1.0.0\n",
+			errorContains: "expected kind Kustomization in aws/kustomization.yaml, got \"\"",
+		},
+		{
+			name:          "case 2: wrong apiVersion",
+			kustomization: "apiVersion: v1\nkind: Kustomization\nresources:\n- v1.0.0\n",
+			errorContains: "expected apiVersion kustomize.config.k8s.io/v1beta1 in aws/kustomization.yaml, got \"v1\"",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			fs := newTestFilesystem(t, map[string]string{
+				"aws/kustomization.yaml":        tc.kustomization,
+				"aws/v1.0.0/kustomization.yaml": kustomization("release.yaml"),
+				"aws/v1.0.0/release.yaml":       releaseManifest("v1.0.0", "active"),
+			})
+
+			err := validateKustomizationTypes(newRun(fs, "aws"))
+			assertError(t, err, tc.errorContains)
+		})
+	}
+}
+
+func Test_Validation_validateAnnotationPrefixes(t *testing.T) {
+	testCases := []struct {
+		name          string
+		annotations   string
+		options       []Option
+		errorContains string
+	}{
+		{
+			name:        "case 0: default prefixes",
+			annotations: "commonAnnotations:\n  giantswarm.io/docs: https://docs.giantswarm.io\n  release.giantswarm.io/provider: aws\n",
+		},
+		{
+			name:          "case 1: foreign prefix",
+			annotations:   "commonAnnotations:\n  giantswarm.io/docs: https://docs.giantswarm.io\n  example.com/owner: team\n",
+			errorContains: "common annotation example.com/owner in aws/v1.0.0/kustomization.yaml must use one of the prefixes giantswarm.io/, release.giantswarm.io/",
+		},
+		{
+			name:        "case 2: configured prefix",
+			annotations: "commonAnnotations:\n  example.com/owner: team\n",
+			options:     []Option{WithAnnotationPrefixes("example.com/")},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			fs := newTestFilesystem(t, map[string]string{
+				"aws/kustomization.yaml":        kustomization("v1.0.0"),
+				"aws/v1.0.0/kustomization.yaml": kustomization("release.yaml") + tc.annotations,
+				"aws/v1.0.0/release.yaml":       releaseManifest("v1.0.0", "active"),
+			})
+
+			err := validateAnnotationPrefixes(newRun(fs, "aws", tc.options...))
+			assertError(t, err, tc.errorContains)
+		})
+	}
+}
+
+func Test_Validation_validateRepositoryAnnotation(t *testing.T) {
+	testCases := []struct {
+		name          string
+		annotations   string
+		options       []Option
+		errorContains string
+	}{
+		{
+			name:        "case 0: check skipped without key",
+			annotations: "",
+		},
+		{
+			name:        "case 1: annotation with default repository",
+			annotations: "commonAnnotations:\n  giantswarm.io/repository: https://github.com/giantswarm/releases\n",
+			options:     []Option{WithRepositoryAnnotation("giantswarm.io/repository")},
+		},
+		{
+			name:          "case 2: annotation missing",
+			annotations:   "commonAnnotations:\n  giantswarm.io/docs: https://docs.giantswarm.io\n",
+			options:       []Option{WithRepositoryAnnotation("giantswarm.io/repository")},
+			errorContains: "common annotation giantswarm.io/repository missing in aws/kustomization.yaml",
+		},
+		{
+			name:          "case 3: annotation with other repository",
+			annotations:   "commonAnnotations:\n  giantswarm.io/repository: https://github.com/giantswarm/releases\n",
+			options:       []Option{WithRepositoryAnnotation("giantswarm.io/repository"), WithRepository("https://github.com/example/releases")},
+			errorContains: `common annotation giantswarm.io/repository in aws/kustomization.yaml must be https://github.com/example/releases, got "https://github.com/giantswarm/releases"`,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			fs := newTestFilesystem(t, map[string]string{
+				"aws/kustomization.yaml":        kustomization("v1.0.0") + tc.annotations,
+				"aws/v1.0.0/kustomization.yaml": kustomization("release.yaml"),
+				"aws/v1.0.0/release.yaml":       releaseManifest("v1.0.0", "active"),
+			})
+
+			err := validateRepositoryAnnotation(newRun(fs, "aws", tc.options...))
+			assertError(t, err, tc.errorContains)
+		})
+	}
+}
+
+func Test_Validation_ProviderNotFound(t *testing.T) {
+	fs := newTestFilesystem(t, validProviderFiles())
+
+	err := Validate(fs, "awss")
+	if !IsProviderNotFound(err) {
+		t.Fatalf("expected provider not found error, got %#v", err)
+	}
+	if !strings.Contains(err.Error(), "awss") {
+		t.Errorf("expected error to name the provider, got %s", err)
+	}
+
+	_, err = ValidateDetailed(fs, "awss")
+	if !IsProviderNotFound(err) {
+		t.Fatalf("expected provider not found error, got %#v", err)
+	}
+}
+
+func Test_Validation_validateReleaseNotesDates(t *testing.T) {
+	testCases := []struct {
+		name          string
+		releaseNotes  string
+		options       []Option
+		errorContains string
+	}{
+		{
+			name:         "case 0: disabled by default",
+			releaseNotes: "# :zap: Giant Swarm Release v1.0.0 for AWS :zap:\n",
+		},
+		{
+			name:         "case 1: ISO date on the second line",
+			releaseNotes: "# :zap: Giant Swarm Release v1.0.0 for AWS :zap:\n\nReleased on 2020-09-01.\n",
+			options:      []Option{WithReleaseNotesDates()},
+		},
+		{
+			name:         "case 2: long date on the first line",
+			releaseNotes: "# :zap: Giant Swarm Release v1.0.0 for AWS (September 1, 2020) :zap:\n",
+			options:      []Option{WithReleaseNotesDates()},
+		},
+		{
+			name:          "case 3: date not matching the manifest",
+			releaseNotes:  "# :zap: Giant Swarm Release v1.0.0 for AWS :zap:\n\nReleased on 2020-08-31.\n",
+			options:       []Option{WithReleaseNotesDates()},
+			errorContains: "expected release notes for aws release v1.0.0 to mention the release date 2020-09-01 on the first or second line",
+		},
+		{
+			name:          "case 4: date further down",
+			releaseNotes:  "# :zap: Giant Swarm Release v1.0.0 for AWS :zap:\n\n## Components\n\nReleased on 2020-09-01.\n",
+			options:       []Option{WithReleaseNotesDates()},
+			errorContains: "to mention the release date 2020-09-01",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			fs := newTestFilesystem(t, map[string]string{
+				"aws/v1.0.0/README.md":    tc.releaseNotes,
+				"aws/v1.0.0/release.yaml": releaseManifest("v1.0.0", "active"),
+			})
+
+			err := validateReleaseNotesDates(newRun(fs, "aws", tc.options...))
+			assertError(t, err, tc.errorContains)
+		})
+	}
+}
+
+func Test_Validation_validateReleaseNotesSections(t *testing.T) {
+	releaseNotes := `# :zap: Giant Swarm Release v1.0.0 for AWS :zap:
+
+## Components
+
+### kubernetes 1.18.9
+`
+
+	testCases := []struct {
+		name          string
+		options       []Option
+		errorContains string
+	}{
+		{
+			name: "case 0: no required sections",
+		},
+		{
+			name:    "case 1: present section",
+			options: []Option{WithRequiredSections("## Components")},
+		},
+		{
+			name:          "case 2: missing section",
+			options:       []Option{WithRequiredSections("## Components", "## Apps")},
+			errorContains: "expected release notes for aws release v1.0.0 to contain section \"## Apps\"",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			fs := newTestFilesystem(t, map[string]string{
+				"aws/v1.0.0/README.md":    releaseNotes,
+				"aws/v1.0.0/release.yaml": releaseManifest("v1.0.0", "active"),
+			})
+
+			err := validateReleaseNotesSections(newRun(fs, "aws", tc.options...))
+			assertError(t, err, tc.errorContains)
+		})
+	}
+}
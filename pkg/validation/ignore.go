@@ -0,0 +1,75 @@
+package validation
+
+import (
+	"regexp"
+
+	"github.com/giantswarm/microerror"
+	"sigs.k8s.io/yaml"
+)
+
+// Ignore acknowledges the findings of a validator for a release which is known not to satisfy
+// it, see WithIgnores.
+type Ignore struct {
+	Validator string `yaml:"validator"`
+	// Provider restricts the ignore to a single provider. It is optional.
+	Provider string `yaml:"provider"`
+	// Release is the name of the release whose findings are acknowledged. Without a release
+	// every finding of the validator is.
+	Release string `yaml:"release"`
+	// Reason tracks why the findings are acknowledged, e.g. an issue URL.
+	Reason string `yaml:"reason"`
+}
+
+// ignoreFile is a list of known validation exceptions like
+//
+//	ignore:
+//	- validator: readme
+//	  release: v1.0.0
+//	  reason: https://github.com/giantswarm/roadmap/issues/1
+type ignoreFile struct {
+	Ignore []Ignore `yaml:"ignore"`
+}
+
+// LoadIgnores parses an ignore file listing known validation exceptions, to be passed to
+// WithIgnores. Every entry must name a validator and give a reason.
+func LoadIgnores(data []byte) ([]Ignore, error) {
+	var file ignoreFile
+	err := yaml.UnmarshalStrict(data, &file)
+	if err != nil {
+		return nil, microerror.Maskf(invalidIgnoresError, "ignore file can't be parsed: %s", err)
+	}
+
+	for i, ignore := range file.Ignore {
+		if ignore.Validator == "" {
+			return nil, microerror.Maskf(invalidIgnoresError, "ignore %d must name a validator", i)
+		}
+		if ignore.Reason == "" {
+			return nil, microerror.Maskf(invalidIgnoresError, "ignore %d for validator %s must give a reason", i, ignore.Validator)
+		}
+	}
+
+	return file.Ignore, nil
+}
+
+// ignored returns the ignore acknowledging the given finding of the currently executing
+// validator, if any. Findings of validators checking the provider as a whole don't carry a
+// release, so they match when their message mentions the release.
+func (r *run) ignored(release string, message string) (Ignore, bool) {
+	for _, ignore := range r.options.ignores {
+		if ignore.Validator != r.validator || (ignore.Provider != "" && ignore.Provider != r.provider) {
+			continue
+		}
+		if ignore.Release == "" || ignore.Release == release || (release == "" && mentionsRelease(message, ignore.Release)) {
+			return ignore, true
+		}
+	}
+
+	return Ignore{}, false
+}
+
+// mentionsRelease returns whether message mentions the given release, not counting releases
+// it is a prefix of like v1.0.10 for v1.0.1.
+func mentionsRelease(message string, release string) bool {
+	pattern := regexp.MustCompile(`(^|[^0-9A-Za-z._~+/-])` + regexp.QuoteMeta(release) + `([^0-9A-Za-z_~+/-]|\.?$|\.[^0-9A-Za-z])`)
+	return pattern.MatchString(message)
+}
@@ -0,0 +1,125 @@
+package validation
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Validation_LoadIgnores(t *testing.T) {
+	testCases := []struct {
+		name         string
+		data         string
+		expected     []Ignore
+		errorMatcher func(error) bool
+	}{
+		{
+			name: "case 0: valid ignore file",
+			data: `ignore:
+- validator: readme
+  release: v1.0.0
+  reason: https://github.com/giantswarm/roadmap/issues/1
+- validator: kustomization
+  provider: azure
+  reason: migration
+`,
+			expected: []Ignore{
+				{Validator: "readme", Release: "v1.0.0", Reason: "https://github.com/giantswarm/roadmap/issues/1"},
+				{Validator: "kustomization", Provider: "azure", Reason: "migration"},
+			},
+		},
+		{
+			name:         "case 1: missing reason",
+			data:         "ignore:\n- validator: readme\n  release: v1.0.0\n",
+			errorMatcher: IsInvalidIgnores,
+		},
+		{
+			name:         "case 2: unknown field",
+			data:         "ignore:\n- validator: readme\n  reason: legacy\n  until: v2.0.0\n",
+			errorMatcher: IsInvalidIgnores,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			ignores, err := LoadIgnores([]byte(tc.data))
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				if diff := cmp.Diff(ignores, tc.expected); diff != "" {
+					t.Fatal(diff)
+				}
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case !tc.errorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+		})
+	}
+}
+
+func Test_Validation_WithIgnores(t *testing.T) {
+	files := validProviderFiles()
+	files["aws/v1.1.0/README.md"] = "# :zap: Giant Swarm Release v1.1.0 for AWS :zap:\n"
+	files["aws/v1.1.0/kustomization.yaml"] = kustomization("release.yaml")
+	files["aws/v1.1.0/release.yaml"] = strings.Replace(releaseManifest("v1.1.0", "active"), "2020-09-01", "2020-10-01", 1)
+	files["aws/kustomization.yaml"] = kustomization("v1.0.0", "v1.1.0")
+	files["README.md"] += "- [v1.1.0](https://github.com/giantswarm/releases/tree/master/aws/v1.1.0)\n"
+	// The app version of v1.0.0 is invalid and the link to it missing, both known issues.
+	files["aws/v1.0.0/release.yaml"] = strings.Replace(files["aws/v1.0.0/release.yaml"], "1.2.3", "1.2", 1)
+	files["README.md"] = strings.Replace(files["README.md"], "- [v1.0.0](https://github.com/giantswarm/releases/tree/master/aws/v1.0.0)\n", "", 1)
+	fs := newTestFilesystem(t, files)
+
+	err := Validate(fs, "aws")
+	if err == nil {
+		t.Fatal("expected an error without ignores")
+	}
+
+	ignores, err := LoadIgnores([]byte(`ignore:
+- validator: crd
+  release: v1.0.0
+  reason: legacy app version
+- validator: versions
+  release: v1.0.0
+  reason: legacy app version
+- validator: readme
+  release: v1.0.0
+  reason: not published
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = Validate(fs, "aws", WithIgnores(ignores))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := ValidateDetailed(fs, "aws", WithIgnores(ignores))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var found []string
+	for _, result := range results {
+		found = append(found, strings.Join([]string{string(result.Severity), result.Validator, result.Release, result.Reason}, " "))
+	}
+	expected := []string{
+		"acknowledged crd v1.0.0 legacy app version",
+		"acknowledged versions v1.0.0 legacy app version",
+		"acknowledged readme  not published",
+	}
+	if diff := cmp.Diff(found, expected); diff != "" {
+		t.Error(diff)
+	}
+
+	// Acknowledging v1.0.0 must not hide findings for v1.1.0.
+	files["aws/v1.1.0/release.yaml"] = strings.Replace(files["aws/v1.1.0/release.yaml"], "1.2.3", "1.2", 1)
+	fs = newTestFilesystem(t, files)
+	err = Validate(fs, "aws", WithIgnores(ignores))
+	assertError(t, err, "aws release v1.1.0")
+}
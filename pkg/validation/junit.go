@@ -0,0 +1,109 @@
+package validation
+
+import (
+	"encoding/xml"
+	"sort"
+	"strings"
+
+	"github.com/giantswarm/microerror"
+)
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// ToJUnit converts the results of ValidateAllProviders or ValidateProviders into a JUnit XML
+// report with a test suite per provider. Every validator is a test case of the suite, which
+// fails once for every error it reported and passes otherwise. Warnings don't fail a test
+// case, they are attached to it as output.
+func ToJUnit(results map[string][]ValidationResult) ([]byte, error) {
+	var providers []string
+	for provider := range results {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+
+	var known []string
+	for _, v := range validators {
+		known = append(known, v.name)
+	}
+
+	report := junitTestSuites{}
+	for _, provider := range providers {
+		// Validators the package doesn't know, if any, follow the known ones.
+		names := append([]string{}, known...)
+		seen := map[string]bool{}
+		for _, name := range known {
+			seen[name] = true
+		}
+		failures := map[string][]string{}
+		warnings := map[string][]string{}
+		for _, result := range results[provider] {
+			if !seen[result.Validator] {
+				seen[result.Validator] = true
+				names = append(names, result.Validator)
+			}
+			if result.Severity == SeverityError {
+				failures[result.Validator] = append(failures[result.Validator], result.Message)
+			} else {
+				warnings[result.Validator] = append(warnings[result.Validator], result.Message)
+			}
+		}
+
+		suite := junitTestSuite{Name: provider}
+		for _, name := range names {
+			output := strings.Join(warnings[name], "\n")
+			if len(failures[name]) == 0 {
+				suite.TestCases = append(suite.TestCases, junitTestCase{
+					Name:      name,
+					ClassName: provider,
+					SystemOut: output,
+				})
+				continue
+			}
+			for _, message := range failures[name] {
+				suite.TestCases = append(suite.TestCases, junitTestCase{
+					Name:      name,
+					ClassName: provider,
+					Failure: &junitFailure{
+						Message: message,
+						Type:    string(SeverityError),
+						Text:    message,
+					},
+					SystemOut: output,
+				})
+				suite.Failures++
+			}
+		}
+		suite.Tests = len(suite.TestCases)
+		report.Suites = append(report.Suites, suite)
+	}
+
+	data, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return append([]byte(xml.Header), data...), nil
+}
@@ -0,0 +1,77 @@
+package validation
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Validation_ToJUnit(t *testing.T) {
+	results := map[string][]ValidationResult{
+		"aws": {
+			{
+				Message:   "expected link in README.md to aws release v1.1.0",
+				Provider:  "aws",
+				Severity:  SeverityError,
+				Validator: "readme",
+			},
+			{
+				Message:   "component kubernetes is downgraded from 1.20.2 in v1.0.0 to 1.19.8 in aws release v1.1.0",
+				Provider:  "aws",
+				Release:   "v1.1.0",
+				Severity:  SeverityWarning,
+				Validator: "component-downgrades",
+			},
+		},
+		"azure": {
+			{
+				Message:   "release v1.0.0 not registered in azure/kustomization.yaml",
+				Provider:  "azure",
+				Severity:  SeverityError,
+				Validator: "kustomization",
+			},
+		},
+		"kvm": nil,
+	}
+
+	data, err := ToJUnit(results)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var report junitTestSuites
+	err = xml.Unmarshal(data, &report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Suites) != 3 || report.Suites[0].Name != "aws" || report.Suites[2].Name != "kvm" {
+		t.Fatalf("expected a test suite per provider, got %s", data)
+	}
+
+	var failed []string
+	for _, suite := range report.Suites {
+		if suite.Tests != len(validators) || len(suite.TestCases) != len(validators) {
+			t.Errorf("expected %d test cases for %s, got %d", len(validators), suite.Name, suite.Tests)
+		}
+		for _, testCase := range suite.TestCases {
+			if testCase.Failure != nil {
+				failed = append(failed, testCase.ClassName+" "+testCase.Name+": "+testCase.Failure.Message)
+			}
+			if testCase.Name == "component-downgrades" && suite.Name == "aws" && testCase.SystemOut == "" {
+				t.Errorf("expected the warning to be attached to the component-downgrades test case")
+			}
+		}
+	}
+
+	expected := []string{
+		"aws readme: expected link in README.md to aws release v1.1.0",
+		"azure kustomization: release v1.0.0 not registered in azure/kustomization.yaml",
+	}
+	if diff := cmp.Diff(failed, expected); diff != "" {
+		t.Error(diff)
+	}
+	if report.Suites[0].Failures != 1 || report.Suites[2].Failures != 0 {
+		t.Errorf("unexpected failure counts in %s", data)
+	}
+}
@@ -0,0 +1,342 @@
+package validation
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/giantswarm/apiextensions/v2/pkg/apis/release/v1alpha1"
+
+	"github.com/giantswarm/releaseclient/pkg/key"
+)
+
+const (
+	defaultArchivedLinkTemplate = "{repository}/tree/master/{provider}/" + key.ArchivedDirectory + "/{release}"
+	defaultMaxFutureDays        = 30
+)
+
+var defaultAllowedCatalogs = []string{
+	"default",
+	"giantswarm",
+}
+
+var defaultKnownProviders = []string{
+	"aws",
+	"azure",
+	"kvm",
+}
+
+var defaultAnnotationPrefixes = []string{
+	"giantswarm.io/",
+	"release.giantswarm.io/",
+}
+
+// VersionPrefix defines whether versions are written with a `v` prefix, see WithVersionPrefix
+// and WithComponentVersionPrefix.
+type VersionPrefix int
+
+const (
+	// VersionPrefixOptional accepts versions with and without `v` prefix.
+	VersionPrefixOptional VersionPrefix = iota
+	// VersionPrefixRequired only accepts versions like v1.2.0.
+	VersionPrefixRequired
+	// VersionPrefixForbidden only accepts versions like 1.2.0.
+	VersionPrefixForbidden
+)
+
+// Option configures optional behaviour of Validate.
+type Option func(o *options)
+
+// Logger receives debug messages about the files read and the outcome of each check.
+type Logger interface {
+	Debugf(format string, args ...interface{})
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Debugf(format string, args ...interface{}) {}
+
+type options struct {
+	allowedCatalogs      []string
+	allowedProviders     []string
+	annotationPrefixes   []string
+	archivedCRD          bool
+	archivedLinkTemplate string
+	baseReleases         []v1alpha1.Release
+	// changedPaths is only taken into account when filterChangedPaths is set so that an
+	// empty change set can be told apart from the option not being used at all.
+	changedPaths       []string
+	componentPrefix    VersionPrefix
+	filterChangedPaths bool
+	crdVersions        []string
+	downgradeWarnings  bool
+	ignores            []Ignore
+	knownProviders     []string
+	logger             Logger
+	maxActiveReleases  int
+	maxFutureDays      int
+	minimumVersions    map[string]string
+	// now is the clock used for date checks, replaced in tests.
+	now                func() time.Time
+	releaseNamePattern *regexp.Regexp
+	releaseNotesDates  bool
+	repository         string
+	// repositoryAnnotation is the commonAnnotations key which must hold repository.
+	repositoryAnnotation string
+	requiredApps         []string
+	requiredLabels       []string
+	requiredSections     []string
+	skippedValidators    []string
+	states               []v1alpha1.ReleaseState
+	sunsetDates          bool
+	versionPrefix        VersionPrefix
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		allowedCatalogs:      defaultAllowedCatalogs,
+		archivedLinkTemplate: defaultArchivedLinkTemplate,
+		annotationPrefixes:   defaultAnnotationPrefixes,
+		knownProviders:       defaultKnownProviders,
+		logger:               nopLogger{},
+		maxFutureDays:        defaultMaxFutureDays,
+		now:                  time.Now,
+		repository:           key.RepositoryURL,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithAllowedCatalogs sets the catalogs apps of a release may reference. It defaults to
+// default and giantswarm. Apps which don't name a catalog are always accepted.
+func WithAllowedCatalogs(catalogs ...string) Option {
+	return func(o *options) {
+		o.allowedCatalogs = catalogs
+	}
+}
+
+// WithAllowedProviders sets the providers which are still supported. The requests file of any
+// other provider must not request versions anymore. Without allowed providers the check is
+// skipped.
+func WithAllowedProviders(providers ...string) Option {
+	return func(o *options) {
+		o.allowedProviders = providers
+	}
+}
+
+// WithAnnotationPrefixes sets the prefixes allowed for commonAnnotations keys in
+// kustomization.yaml files. It defaults to giantswarm.io/ and release.giantswarm.io/.
+func WithAnnotationPrefixes(prefixes ...string) Option {
+	return func(o *options) {
+		o.annotationPrefixes = prefixes
+	}
+}
+
+// WithArchivedCRDValidation validates archived releases against the CRD as well, so that they
+// don't silently become invalid after a CRD change. It is off by default.
+func WithArchivedCRDValidation() Option {
+	return func(o *options) {
+		o.archivedCRD = true
+	}
+}
+
+// WithArchivedLinkTemplate sets the URL the README must link to for every archived release.
+// The placeholders {repository}, {provider} and {release} are replaced by the repository set
+// with WithRepository, the provider and the release name. It defaults to
+// "{repository}/tree/master/{provider}/archived/{release}".
+func WithArchivedLinkTemplate(template string) Option {
+	return func(o *options) {
+		o.archivedLinkTemplate = template
+	}
+}
+
+// WithBaseReleases sets the releases of the provider before the change under validation, e.g.
+// as found on the target branch of a pull request. Releases present in both are checked for
+// legal state transitions. Without base releases the check is skipped.
+func WithBaseReleases(releases []v1alpha1.Release) Option {
+	return func(o *options) {
+		o.baseReleases = releases
+	}
+}
+
+// WithChangedPaths restricts release-scoped validators to the releases touched by the given
+// paths, e.g. the output of `git diff --name-only`. Paths are relative to the repository root.
+// A path inside a release directory selects that release, a file directly in the provider
+// directory (like requests.yaml) selects all releases of the provider. Checks covering the
+// provider as a whole, like the README and kustomization checks, always consider every release.
+func WithChangedPaths(paths []string) Option {
+	return func(o *options) {
+		o.changedPaths = paths
+		o.filterChangedPaths = true
+	}
+}
+
+// WithComponentVersionPrefix sets whether the versions of components and apps in release
+// manifests must or must not have a `v` prefix, so that downstream tools can compare them
+// exactly. By default both are accepted.
+func WithComponentVersionPrefix(prefix VersionPrefix) Option {
+	return func(o *options) {
+		o.componentPrefix = prefix
+	}
+}
+
+// WithCRDVersions restricts CRD schema validation to the given versions of the Release CRD,
+// e.g. "v1alpha1". By default releases are validated against every version.
+func WithCRDVersions(versions ...string) Option {
+	return func(o *options) {
+		o.crdVersions = versions
+	}
+}
+
+// WithDowngradeWarnings enables warnings about components whose version decreases between a
+// release and its semver predecessor. It is off by default since downgrades are sometimes
+// intentional.
+func WithDowngradeWarnings() Option {
+	return func(o *options) {
+		o.downgradeWarnings = true
+	}
+}
+
+// WithIgnores acknowledges the findings matching the given ignores, see LoadIgnores. They are
+// still reported, with SeverityAcknowledged and the reason of the ignore, but never make
+// Validate fail. Validators checking single releases then run once per release so that an
+// acknowledged release doesn't hide findings for the others.
+func WithIgnores(ignores []Ignore) Option {
+	return func(o *options) {
+		o.ignores = ignores
+	}
+}
+
+// WithKnownProviders sets the names provider directories may have. It defaults to aws, azure
+// and kvm.
+func WithKnownProviders(providers ...string) Option {
+	return func(o *options) {
+		o.knownProviders = providers
+	}
+}
+
+// WithLogger sets the logger used to trace validation. By default nothing is logged.
+func WithLogger(logger Logger) Option {
+	return func(o *options) {
+		if logger == nil {
+			logger = nopLogger{}
+		}
+		o.logger = logger
+	}
+}
+
+// WithMaxActiveReleases enables a warning when a provider has more than the given number of
+// active releases, hinting that old ones should be archived. It is off by default.
+func WithMaxActiveReleases(max int) Option {
+	return func(o *options) {
+		o.maxActiveReleases = max
+	}
+}
+
+// WithMaxFutureDays sets how many days into the future an active release may be dated before
+// a warning is reported. It defaults to 30 days.
+func WithMaxFutureDays(days int) Option {
+	return func(o *options) {
+		o.maxFutureDays = days
+	}
+}
+
+// WithMinimumVersions sets the lowest versions of components or apps, keyed by name, e.g.
+// {"kubernetes": "1.18.0"}, which active releases may ship. Unlike requests these are an
+// org-wide policy without exceptions.
+func WithMinimumVersions(minimums map[string]string) Option {
+	return func(o *options) {
+		o.minimumVersions = minimums
+	}
+}
+
+// WithReleaseNamePattern sets a regular expression which release names must match in addition
+// to being valid semver versions, e.g. to restrict a provider to a major version.
+func WithReleaseNamePattern(pattern *regexp.Regexp) Option {
+	return func(o *options) {
+		o.releaseNamePattern = pattern
+	}
+}
+
+// WithReleaseNotesDates requires the release notes to mention the release date of the
+// manifest on their first or second non-empty line, e.g. as 2020-09-01. It is off by default.
+func WithReleaseNotesDates() Option {
+	return func(o *options) {
+		o.releaseNotesDates = true
+	}
+}
+
+// WithRequiredApps sets the names of apps which every active release must contain.
+func WithRequiredApps(names ...string) Option {
+	return func(o *options) {
+		o.requiredApps = names
+	}
+}
+
+// WithRequiredLabels sets metadata label keys, e.g. key.ProviderLabel, which every release
+// must carry.
+func WithRequiredLabels(labels ...string) Option {
+	return func(o *options) {
+		o.requiredLabels = labels
+	}
+}
+
+// WithRequiredSections sets markdown section headings, e.g. "## Apps", which the release notes
+// of every release must contain.
+func WithRequiredSections(headings ...string) Option {
+	return func(o *options) {
+		o.requiredSections = headings
+	}
+}
+
+// WithSkippedValidators sets the names of validators, e.g. "release-notes-sections", which
+// are not run.
+func WithSkippedValidators(names ...string) Option {
+	return func(o *options) {
+		o.skippedValidators = names
+	}
+}
+
+// WithStates restricts the releases which are validated to those in the given states, e.g.
+// to exempt WIP releases which aren't published yet. It applies to the checks of individual
+// releases and to the README links, while checks of the provider as a whole like the
+// kustomization still consider every release. By default releases in any state are validated.
+func WithStates(states ...v1alpha1.ReleaseState) Option {
+	return func(o *options) {
+		o.states = states
+	}
+}
+
+// WithSunsetDates requires deprecated releases to carry a sunset date in the future, see
+// key.SunsetDate. It is off by default.
+func WithSunsetDates() Option {
+	return func(o *options) {
+		o.sunsetDates = true
+	}
+}
+
+// WithVersionPrefix sets whether the version on the first line of release notes must or must
+// not have a `v` prefix. By default both are accepted.
+func WithVersionPrefix(prefix VersionPrefix) Option {
+	return func(o *options) {
+		o.versionPrefix = prefix
+	}
+}
+
+// WithRepository sets the base URL of the repository the README links to for both active and
+// archived releases. It defaults to the giantswarm/releases repository.
+func WithRepository(url string) Option {
+	return func(o *options) {
+		o.repository = url
+	}
+}
+
+// WithRepositoryAnnotation requires the commonAnnotations of the provider kustomization.yaml
+// to contain the given key with the repository URL set with WithRepository as its value.
+// Without a key the check is skipped.
+func WithRepositoryAnnotation(key string) Option {
+	return func(o *options) {
+		o.repositoryAnnotation = key
+	}
+}
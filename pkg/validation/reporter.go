@@ -0,0 +1,22 @@
+package validation
+
+// Reporter receives progress events from ValidateWithReporter, e.g. to drive a spinner or
+// progress bar. Events are delivered synchronously and in order.
+type Reporter interface {
+	// ReleaseStarted is called before the release-scoped validators run for a release.
+	ReleaseStarted(release string)
+	// ReleaseFinished is called after the release-scoped validators ran for a release, with
+	// the first error found, if any.
+	ReleaseFinished(release string, err error)
+	// ValidatorStarted is called before a validator runs.
+	ValidatorStarted(validator string)
+	// ValidatorFinished is called after a validator ran, with the error it returned, if any.
+	ValidatorFinished(validator string, err error)
+}
+
+type nopReporter struct{}
+
+func (nopReporter) ReleaseStarted(release string)                 {}
+func (nopReporter) ReleaseFinished(release string, err error)     {}
+func (nopReporter) ValidatorStarted(validator string)             {}
+func (nopReporter) ValidatorFinished(validator string, err error) {}
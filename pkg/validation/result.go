@@ -0,0 +1,26 @@
+package validation
+
+// Severity describes how a finding affects the outcome of a validation.
+type Severity string
+
+const (
+	// SeverityError findings make Validate fail.
+	SeverityError Severity = "error"
+	// SeverityWarning findings are reported but never make Validate fail.
+	SeverityWarning Severity = "warning"
+	// SeverityAcknowledged findings matched an ignore, see WithIgnores. They are reported
+	// but never make Validate fail.
+	SeverityAcknowledged Severity = "acknowledged"
+)
+
+// ValidationResult is a single finding reported by a validator.
+type ValidationResult struct {
+	Message  string
+	Provider string
+	// Reason is the reason of the ignore an acknowledged finding matched.
+	Reason string
+	// Release is the name of the release the finding concerns, if any.
+	Release   string
+	Severity  Severity
+	Validator string
+}
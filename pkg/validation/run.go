@@ -0,0 +1,318 @@
+package validation
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/giantswarm/apiextensions/v2/pkg/apis/release/v1alpha1"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/releaseclient/pkg/filesystem"
+	"github.com/giantswarm/releaseclient/pkg/key"
+)
+
+// run holds the state shared by all validators during a single Validate call.
+type run struct {
+	fs       filesystem.Filesystem
+	provider string
+	options  options
+	reporter Reporter
+
+	// release restricts findReleases to the release with this name, if set.
+	release string
+
+	// validator is the name of the validator currently executing.
+	validator string
+	results   []ValidationResult
+}
+
+func newRun(fs filesystem.Filesystem, provider string, opts ...Option) *run {
+	return &run{
+		fs:       fs,
+		provider: provider,
+		options:  newOptions(opts),
+		reporter: nopReporter{},
+	}
+}
+
+// execute runs the given validators and records a result for every error they return. With
+// failFast set it stops at and returns the first error.
+func (r *run) execute(validators []validator, failFast bool) error {
+	exists, err := r.exists(r.provider)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	if !exists {
+		return microerror.Maskf(providerNotFoundError, "provider directory %s does not exist", r.provider)
+	}
+
+	// Acknowledged findings must not hide the findings for other releases, and collected
+	// findings should name the release they concern, so release-scoped validators run once per
+	// release then.
+	if len(r.options.ignores) > 0 || !failFast {
+		err = r.executeByRelease(validators, failFast)
+	} else {
+		err = r.executeValidators(validators, failFast)
+	}
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+// executeByRelease runs the given validators which only need a release manifest, see
+// releaseValidators, once per release between ReleaseStarted and ReleaseFinished events,
+// followed by the remaining validators for the provider as a whole. The provider must exist.
+func (r *run) executeByRelease(validators []validator, failFast bool) error {
+	releaseScoped := map[string]bool{}
+	for _, v := range releaseValidators {
+		releaseScoped[v.name] = true
+	}
+	var perRelease, perProvider []validator
+	for _, v := range validators {
+		if releaseScoped[v.name] {
+			perRelease = append(perRelease, v)
+		} else {
+			perProvider = append(perProvider, v)
+		}
+	}
+
+	releases, err := r.findReleases(false)
+	// Of the release-scoped validators only the CRD check considers archived releases.
+	if err == nil && r.options.archivedCRD {
+		var archived []v1alpha1.Release
+		archived, err = r.findReleases(true)
+		releases = append(releases, archived...)
+	}
+	// Releases which don't load leave the release-scoped validators nothing to run for, so all
+	// validators run for the provider as a whole then. They report the problem next to the
+	// findings of validators checking the repository structure.
+	if err != nil {
+		r.options.logger.Debugf("validating provider %s as a whole, releases failed to load: %s", r.provider, err)
+		err = r.executeValidators(validators, failFast)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		return nil
+	}
+	for _, release := range releases {
+		r.release = release.Name
+		r.reporter.ReleaseStarted(release.Name)
+		err = r.executeValidators(perRelease, failFast)
+		r.reporter.ReleaseFinished(release.Name, err)
+		if err != nil {
+			r.release = ""
+			return microerror.Mask(err)
+		}
+	}
+	r.release = ""
+
+	err = r.executeValidators(perProvider, failFast)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+// executeValidators runs the given validators like execute but without checking that the
+// provider exists.
+func (r *run) executeValidators(validators []validator, failFast bool) error {
+	skipped := map[string]bool{}
+	for _, name := range r.options.skippedValidators {
+		skipped[name] = true
+	}
+
+	for _, v := range validators {
+		if skipped[v.name] {
+			r.options.logger.Debugf("validator %s skipped for provider %s", v.name, r.provider)
+			continue
+		}
+
+		r.validator = v.name
+		r.reporter.ValidatorStarted(v.name)
+		err := v.validate(r)
+		r.reporter.ValidatorFinished(v.name, err)
+		if err != nil {
+			if r.report(SeverityError, r.release, err.Error()) == SeverityAcknowledged {
+				r.options.logger.Debugf("validator %s failed for provider %s with acknowledged finding: %s", v.name, r.provider, err)
+				continue
+			}
+			r.options.logger.Debugf("validator %s failed for provider %s: %s", v.name, r.provider, err)
+			if failFast {
+				return microerror.Mask(err)
+			}
+			continue
+		}
+		r.options.logger.Debugf("validator %s passed for provider %s", v.name, r.provider)
+	}
+
+	return nil
+}
+
+// report records a finding for the currently executing validator and returns its severity,
+// which is SeverityAcknowledged if it matches an ignore, see WithIgnores.
+func (r *run) report(severity Severity, release string, message string) Severity {
+	result := ValidationResult{
+		Message:   message,
+		Provider:  r.provider,
+		Release:   release,
+		Severity:  severity,
+		Validator: r.validator,
+	}
+	if ignore, ok := r.ignored(release, message); ok {
+		result.Reason = ignore.Reason
+		result.Severity = SeverityAcknowledged
+	}
+	r.results = append(r.results, result)
+
+	return result.Severity
+}
+
+// warnf records a warning about the given release for the currently executing validator.
+func (r *run) warnf(release string, format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	r.options.logger.Debugf("validator %s warning: %s", r.validator, message)
+	r.report(SeverityWarning, release, message)
+}
+
+func (r *run) readFile(path string) ([]byte, error) {
+	r.options.logger.Debugf("reading file %s", path)
+	content, err := r.fs.ReadFile(path)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	return content, nil
+}
+
+func (r *run) exists(path string) (bool, error) {
+	r.options.logger.Debugf("checking existence of %s", path)
+	exists, err := r.fs.Exists(path)
+	if err != nil {
+		return false, microerror.Mask(err)
+	}
+	return exists, nil
+}
+
+func (r *run) listDirectories(path string) ([]string, error) {
+	r.options.logger.Debugf("listing directories in %s", path)
+	directories, err := r.fs.ListDirectories(path)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	return directories, nil
+}
+
+// findAllReleases returns every release of the provider regardless of WithChangedPaths. It is
+// meant for validators checking the provider as a whole. A provider without an archived
+// directory has no archived releases.
+func (r *run) findAllReleases(archived bool) ([]v1alpha1.Release, error) {
+	if archived {
+		exists, err := r.exists(filepath.Join(r.provider, key.ArchivedDirectory))
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		if !exists {
+			return nil, nil
+		}
+	}
+
+	r.options.logger.Debugf("finding releases for provider %s (archived: %t)", r.provider, archived)
+	releases, err := r.fs.FindReleases(r.provider, archived)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	r.options.logger.Debugf("found %d releases for provider %s (archived: %t)", len(releases), r.provider, archived)
+	return releases, nil
+}
+
+// findReleases returns the releases of the provider which release-scoped validators should
+// consider.
+func (r *run) findReleases(archived bool) ([]v1alpha1.Release, error) {
+	releases, err := r.findAllReleases(archived)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	names := map[string]bool{}
+	for _, release := range releases {
+		names[release.Name] = true
+	}
+
+	if r.release != "" {
+		var filtered []v1alpha1.Release
+		for _, release := range releases {
+			if release.Name == r.release {
+				filtered = append(filtered, release)
+			}
+		}
+		releases = filtered
+	}
+
+	if len(r.options.states) > 0 {
+		var filtered []v1alpha1.Release
+		for _, release := range releases {
+			if r.stateSelected(release) {
+				filtered = append(filtered, release)
+			}
+		}
+		releases = filtered
+	}
+
+	if !r.options.filterChangedPaths {
+		return releases, nil
+	}
+
+	touched := map[string]bool{}
+	for _, changed := range r.options.changedPaths {
+		segments := strings.Split(strings.TrimPrefix(path.Clean(filepath.ToSlash(changed)), "./"), "/")
+		if len(segments) < 2 || segments[0] != r.provider {
+			continue
+		}
+		segments = segments[1:]
+
+		if segments[0] == key.ArchivedDirectory {
+			if !archived || len(segments) < 2 {
+				continue
+			}
+			segments = segments[1:]
+		} else if archived {
+			continue
+		}
+
+		if len(segments) == 1 && !names[segments[0]] {
+			// A file directly in the provider directory affects every release.
+			return releases, nil
+		}
+		touched[segments[0]] = true
+	}
+
+	var filtered []v1alpha1.Release
+	for _, release := range releases {
+		if touched[release.Name] {
+			filtered = append(filtered, release)
+		} else {
+			r.options.logger.Debugf("skipping %s release %s not touched by changed paths", r.provider, release.Name)
+		}
+	}
+
+	return filtered, nil
+}
+
+// stateSelected returns whether the given release is in one of the states selected with
+// WithStates. Without selected states every release is.
+func (r *run) stateSelected(release v1alpha1.Release) bool {
+	if len(r.options.states) == 0 {
+		return true
+	}
+	for _, state := range r.options.states {
+		if release.Spec.State == state {
+			return true
+		}
+	}
+	r.options.logger.Debugf("skipping %s release %s in state %s", r.provider, release.Name, release.Spec.State)
+	return false
+}
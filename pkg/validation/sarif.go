@@ -0,0 +1,169 @@
+package validation
+
+import (
+	"encoding/json"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/releaseclient/pkg/filesystem"
+	"github.com/giantswarm/releaseclient/pkg/key"
+)
+
+const (
+	sarifSchema   = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion  = "2.1.0"
+	sarifToolName = "releaseclient"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules,omitempty"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId,omitempty"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// ToSARIF converts the results of ValidateDetailed for the given filesystem into a SARIF 2.1.0
+// log for GitHub code scanning. Every result becomes a SARIF result whose rule is the
+// validator which reported it. Results are located at the file they concern, see sarifURI.
+func ToSARIF(fs filesystem.Filesystem, results []ValidationResult) ([]byte, error) {
+	rules := map[string]bool{}
+	sarifResults := []sarifResult{}
+	for _, result := range results {
+		if result.Validator != "" {
+			rules[result.Validator] = true
+		}
+
+		sarifResult := sarifResult{
+			RuleID:  result.Validator,
+			Level:   sarifLevel(result.Severity),
+			Message: sarifMessage{Text: result.Message},
+		}
+		if result.Provider != "" {
+			uri, err := sarifURI(fs, result)
+			if err != nil {
+				return nil, microerror.Mask(err)
+			}
+			sarifResult.Locations = []sarifLocation{
+				{
+					PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: sarifArtifactLocation{URI: uri},
+					},
+				},
+			}
+		}
+		sarifResults = append(sarifResults, sarifResult)
+	}
+
+	driver := sarifDriver{Name: sarifToolName}
+	for rule := range rules {
+		driver.Rules = append(driver.Rules, sarifRule{ID: rule})
+	}
+	sort.Slice(driver.Rules, func(i, j int) bool {
+		return driver.Rules[i].ID < driver.Rules[j].ID
+	})
+
+	log := sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{
+			{
+				Tool:    sarifTool{Driver: driver},
+				Results: sarifResults,
+			},
+		},
+	}
+	data, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return data, nil
+}
+
+func sarifLevel(severity Severity) string {
+	switch severity {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// sarifURI returns the path of the file the given result concerns. Findings about the requests,
+// the provider kustomization or the README are located at that file. Other findings about a
+// release are located at its release notes or manifest, within the archived directory for
+// archived releases. The remaining findings concern the provider directory as a whole.
+func sarifURI(fs filesystem.Filesystem, result ValidationResult) (string, error) {
+	switch result.Validator {
+	case "requests", "requests-canonical", "requests-order", "redundant-exceptions", "retired-provider-requests":
+		return path.Join(result.Provider, key.RequestsFilename), nil
+	case "kustomization", "annotation-prefixes", "repository-annotation":
+		return path.Join(result.Provider, key.KustomizationFilename), nil
+	case "readme":
+		return key.ReadmeFilename, nil
+	}
+	if result.Release == "" {
+		return result.Provider, nil
+	}
+
+	filename := key.ReleaseFilename
+	if strings.HasPrefix(result.Validator, "release-notes") {
+		filename = key.ReadmeFilename
+	}
+	for _, dir := range []string{path.Join(result.Provider, result.Release), path.Join(result.Provider, key.ArchivedDirectory, result.Release)} {
+		exists, err := fs.Exists(path.Join(dir, filename))
+		if err != nil {
+			return "", microerror.Mask(err)
+		}
+		if exists {
+			return path.Join(dir, filename), nil
+		}
+	}
+
+	return path.Join(result.Provider, result.Release), nil
+}
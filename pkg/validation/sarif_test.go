@@ -0,0 +1,226 @@
+package validation
+
+import (
+	"encoding/json"
+	"testing"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiservervalidation "k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+)
+
+// sarifSubset is the part of the SARIF 2.1.0 schema which ToSARIF makes use of, including
+// its required properties and the allowed result levels.
+const sarifSubset = `{
+  "type": "object",
+  "required": ["version", "runs"],
+  "properties": {
+    "$schema": {"type": "string"},
+    "version": {"type": "string", "enum": ["2.1.0"]},
+    "runs": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "required": ["tool"],
+        "properties": {
+          "tool": {
+            "type": "object",
+            "required": ["driver"],
+            "properties": {
+              "driver": {
+                "type": "object",
+                "required": ["name"],
+                "properties": {
+                  "name": {"type": "string"},
+                  "rules": {
+                    "type": "array",
+                    "items": {
+                      "type": "object",
+                      "required": ["id"],
+                      "properties": {"id": {"type": "string"}}
+                    }
+                  }
+                }
+              }
+            }
+          },
+          "results": {
+            "type": "array",
+            "items": {
+              "type": "object",
+              "required": ["message"],
+              "properties": {
+                "ruleId": {"type": "string"},
+                "level": {"type": "string", "enum": ["none", "note", "warning", "error"]},
+                "message": {
+                  "type": "object",
+                  "required": ["text"],
+                  "properties": {"text": {"type": "string"}}
+                },
+                "locations": {
+                  "type": "array",
+                  "items": {
+                    "type": "object",
+                    "properties": {
+                      "physicalLocation": {
+                        "type": "object",
+                        "properties": {
+                          "artifactLocation": {
+                            "type": "object",
+                            "properties": {"uri": {"type": "string"}}
+                          }
+                        }
+                      }
+                    }
+                  }
+                }
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+func Test_Validation_ToSARIF(t *testing.T) {
+	var props v1.JSONSchemaProps
+	err := json.Unmarshal([]byte(sarifSubset), &props)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var internal apiextensions.JSONSchemaProps
+	err = v1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(&props, &internal, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	validator, _, err := apiservervalidation.NewSchemaValidator(&apiextensions.CustomResourceValidation{OpenAPIV3Schema: &internal})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := []ValidationResult{
+		{
+			Message:   "expected link in README.md to aws release v1.0.0",
+			Provider:  "aws",
+			Severity:  SeverityError,
+			Validator: "readme",
+		},
+		{
+			Message:   "component kubernetes is downgraded from 1.20.2 in v0.9.0 to 1.18.9 in aws release v1.0.0",
+			Provider:  "aws",
+			Release:   "v1.0.0",
+			Severity:  SeverityWarning,
+			Validator: "component-downgrades",
+		},
+		{
+			Message:   "aws release v0.9.0 is invalid against CRD version v1alpha1",
+			Provider:  "aws",
+			Release:   "v0.9.0",
+			Severity:  SeverityError,
+			Validator: "crd",
+		},
+		{
+			Message:   "release notes of aws release v0.9.0 are missing the section ## Apps",
+			Provider:  "aws",
+			Release:   "v0.9.0",
+			Severity:  SeverityError,
+			Validator: "release-notes-sections",
+		},
+		{
+			Message:   "exception for aws release v1.0.0 from request kubernetes >= 1.18.0 in aws/requests.yaml is redundant, the release already satisfies it",
+			Provider:  "aws",
+			Release:   "v1.0.0",
+			Severity:  SeverityWarning,
+			Validator: "redundant-exceptions",
+		},
+		{
+			Message:   "directory aws/wip is not a valid release name",
+			Provider:  "aws",
+			Severity:  SeverityError,
+			Validator: "release-directories",
+		},
+	}
+
+	fs := newTestFilesystem(t, validProviderFiles())
+	data, err := ToSARIF(fs, results)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var document interface{}
+	err = json.Unmarshal(data, &document)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := validator.Validate(document)
+	if !result.IsValid() {
+		t.Fatalf("expected valid SARIF, got errors %v", result.Errors)
+	}
+
+	var log sarifLog
+	err = json.Unmarshal(data, &log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(log.Runs) != 1 || len(log.Runs[0].Results) != len(results) {
+		t.Fatalf("expected a single run with %d results, got %s", len(results), data)
+	}
+
+	// Every finding is located at an existing file, except for findings concerning the
+	// provider directory as a whole.
+	expected := []string{
+		"README.md",
+		"aws/v1.0.0/release.yaml",
+		"aws/archived/v0.9.0/release.yaml",
+		"aws/archived/v0.9.0/README.md",
+		"aws/requests.yaml",
+		"aws",
+	}
+	for i, uri := range expected {
+		actual := log.Runs[0].Results[i].Locations[0].PhysicalLocation.ArtifactLocation.URI
+		if actual != uri {
+			t.Errorf("expected finding %d to be located at %s, got %s", i, uri, actual)
+		}
+		exists, err := fs.Exists(actual)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !exists {
+			t.Errorf("expected location %s of finding %d to exist", actual, i)
+		}
+	}
+	if level := log.Runs[0].Results[1].Level; level != "warning" {
+		t.Errorf("expected warning level, got %s", level)
+	}
+}
+
+func Test_Validation_ToSARIF_ArchivedRelease(t *testing.T) {
+	files := validProviderFiles()
+	files["aws/archived/v0.9.0/release.yaml"] = releaseManifest("v0.9.0", "released")
+	fs := newTestFilesystem(t, files)
+
+	results, err := ValidateDetailed(fs, "aws", WithArchivedCRDValidation())
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ToSARIF(fs, results)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var log sarifLog
+	err = json.Unmarshal(data, &log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var uris []string
+	for _, result := range log.Runs[0].Results {
+		if result.RuleID == "crd" {
+			uris = append(uris, result.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+		}
+	}
+	if len(uris) != 1 || uris[0] != "aws/archived/v0.9.0/release.yaml" {
+		t.Errorf("expected the CRD finding to be located at aws/archived/v0.9.0/release.yaml, got %v", uris)
+	}
+}
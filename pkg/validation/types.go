@@ -1,7 +1,51 @@
 package validation
 
 type kustomizationFile struct {
+	APIVersion        string            `yaml:"apiVersion"`
+	Kind              string            `yaml:"kind"`
 	CommonAnnotations map[string]string `yaml:"commonAnnotations"`
 	Resources         []string          `yaml:"resources"`
 	Transformers      []string          `yaml:"transformers"`
 }
+
+// releaseAppsFile holds the parts of a release.yaml which v1alpha1.Release doesn't model.
+type releaseAppsFile struct {
+	Spec struct {
+		Apps []struct {
+			Catalog string `yaml:"catalog"`
+			Name    string `yaml:"name"`
+		} `yaml:"apps"`
+	} `yaml:"spec"`
+}
+
+// releaseDateFile holds the raw date of a release.yaml, before it is parsed as a time.
+type releaseDateFile struct {
+	Spec struct {
+		Date string `yaml:"date"`
+	} `yaml:"spec"`
+}
+
+// releaseVersionFile holds the spec.version some generators write into a release.yaml, which
+// v1alpha1.ReleaseSpec doesn't model.
+type releaseVersionFile struct {
+	Spec struct {
+		Version string `yaml:"version"`
+	} `yaml:"spec"`
+}
+
+// releaseIndexFile is a helm-style index of the published releases, keyed by provider.
+type releaseIndexFile struct {
+	Entries map[string][]struct {
+		Version string `yaml:"version"`
+	} `yaml:"entries"`
+}
+
+// componentLockFile pins approved component versions by component name.
+type componentLockFile struct {
+	Components map[string]string `yaml:"components"`
+}
+
+type validator struct {
+	name     string
+	validate func(r *run) error
+}